@@ -3,19 +3,24 @@ package main
 import (
 	"fmt"
 	"os"
+	_ "time/tzdata" // site time zones on systems without a zone database
 
 	"driffaud.fr/odin/internal/app"
-	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/cli"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	if err := i18n.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "error initializing i18n: %v\n", err)
-		os.Exit(1)
+	cfg, args, code, ok := cli.Setup(os.Args[1:], os.Stderr)
+	if !ok {
+		os.Exit(code)
+	}
+
+	if len(args) > 0 {
+		os.Exit(cli.Run(cfg, args, os.Stdout, os.Stderr))
 	}
 
-	model := app.InitialModel()
+	model := app.InitialModel(cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error running program: %v\n", err)
@@ -13,6 +13,14 @@ type KeyMap struct {
 	Quit           key.Binding
 	AddFavorite    key.Binding
 	RemoveFavorite key.Binding
+	Rank           key.Binding
+	Profile        key.Binding
+	Models         key.Binding
+	Clock          key.Binding
+	PrevNight      key.Binding
+	NextNight      key.Binding
+	ScrollUp       key.Binding
+	ScrollDown     key.Binding
 	State          ApplicationState
 }
 
@@ -21,11 +29,13 @@ type KeyMap struct {
 func (k KeyMap) ShortHelp() []key.Binding {
 	switch k.State {
 	case StatePlace:
-		return []key.Binding{k.Tab, k.Enter, k.Quit}
-	case StateResults:
+		return []key.Binding{k.Tab, k.Enter, k.Rank, k.Quit}
+	case StateResults, StateRanking:
 		return []key.Binding{k.Enter, k.Back, k.Quit}
+	case StateModels:
+		return []key.Binding{k.Back, k.Quit}
 	case StateWeather:
-		bindings := []key.Binding{k.Back, k.Quit}
+		bindings := []key.Binding{k.Back, k.Quit, k.Profile, k.Models, k.Clock, k.PrevNight, k.NextNight, k.ScrollUp, k.ScrollDown}
 		if k.AddFavorite.Enabled() {
 			bindings = append(bindings, k.AddFavorite)
 		}
@@ -70,6 +80,38 @@ func NewKeyMap() KeyMap {
 			key.WithKeys("f3"),
 			key.WithHelp("f3", i18n.T("key_help.remove_favorite", nil)),
 		),
+		Rank: key.NewBinding(
+			key.WithKeys("f4"),
+			key.WithHelp("f4", i18n.T("key_help.rank", nil)),
+		),
+		Profile: key.NewBinding(
+			key.WithKeys("f5"),
+			key.WithHelp("f5", i18n.T("key_help.profile", nil)),
+		),
+		Models: key.NewBinding(
+			key.WithKeys("f6"),
+			key.WithHelp("f6", i18n.T("key_help.models", nil)),
+		),
+		Clock: key.NewBinding(
+			key.WithKeys("f7"),
+			key.WithHelp("f7", i18n.T("key_help.clock", nil)),
+		),
+		PrevNight: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", i18n.T("key_help.prev_night", nil)),
+		),
+		NextNight: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", i18n.T("key_help.next_night", nil)),
+		),
+		ScrollUp: key.NewBinding(
+			key.WithKeys("up", "pgup"),
+			key.WithHelp("↑/pgup", i18n.T("key_help.scroll_up", nil)),
+		),
+		ScrollDown: key.NewBinding(
+			key.WithKeys("down", "pgdown"),
+			key.WithHelp("↓/pgdown", i18n.T("key_help.scroll_down", nil)),
+		),
 	}
 }
 
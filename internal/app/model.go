@@ -4,10 +4,13 @@ import (
 	"time"
 
 	"driffaud.fr/odin/internal/app/ui"
+	"driffaud.fr/odin/internal/config"
 	"driffaud.fr/odin/internal/domain"
 	"driffaud.fr/odin/internal/platform/api/openmeteo"
 	"driffaud.fr/odin/internal/platform/api/photon"
 	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/ranking"
+	"driffaud.fr/odin/internal/verification"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -24,19 +27,26 @@ const (
 	StateResults ApplicationState = "results"
 	StateWeather ApplicationState = "weather"
 	StateLoading ApplicationState = "loading"
+	StateRanking ApplicationState = "ranking"
+	StateModels  ApplicationState = "models"
 )
 
 // Model represents the application model
 type Model struct {
+	cfg           config.Config
 	width, height int
 	state         ApplicationState
 	placeModel    ui.PlaceModel
 	weatherModel  ui.WeatherModel
+	rankingModel  ui.RankingModel
+	modelsModel   ui.ModelsModel
 	placesList    list.Model
 	weatherData   domain.WeatherData
 	selectedPlace domain.Place
 	spinner       spinner.Model
 	favorites     *storage.FavoritesStore
+	recorder      *verification.Recorder // nil when the forecast history cannot be loaded
+	recording     *openmeteo.Client      // records the forecasts of the favorites when recorder is set
 	err           error
 	keyMap        KeyMap
 	help          help.Model
@@ -52,8 +62,23 @@ type weatherResultMsg struct {
 	err  error
 }
 
+type rankingResultMsg struct {
+	entries []ranking.Entry
+	profile string
+}
+
+type verificationResultMsg struct {
+	place   domain.Place
+	results []verification.Result
+}
+
+type modelsResultMsg struct {
+	comparison domain.ModelComparison
+	err        error
+}
+
 // InitialModel returns the initial application model
-func InitialModel() Model {
+func InitialModel(cfg config.Config) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Moon
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
@@ -63,29 +88,62 @@ func InitialModel() Model {
 		favStore = &storage.FavoritesStore{}
 	}
 
+	recording := openmeteo.DefaultClient
+	recorder, err := verification.NewRecorder(favStore.Favorites)
+	if err == nil {
+		recording = openmeteo.DefaultClient.WithRecorder(recorder)
+	}
+
 	placeModel := ui.NewPlaceModel(favStore)
 	helpModel := help.New()
 	helpModel.ShowAll = false
 
-	return Model{
+	m := Model{
+		cfg:        cfg,
 		state:      StatePlace,
 		placeModel: placeModel,
 		placesList: ui.InitResultsList(),
 		spinner:    s,
 		favorites:  favStore,
+		recorder:   recorder,
+		recording:  recording,
 		err:        nil,
 		keyMap:     NewKeyMap(),
 		help:       helpModel,
 	}
+
+	if cfg.HasDefaultPlace() {
+		place, err := cfg.ResolveDefaultPlace(favStore)
+		if err != nil {
+			m.err = err
+		} else {
+			m.selectedPlace = place
+			m.state = StateLoading
+		}
+	}
+
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return tea.Sequence(
+	cmds := []tea.Cmd{
 		m.placeModel.Init(),
 		m.weatherModel.Init(),
 		tea.SetWindowTitle("Odin"),
-	)
+	}
+
+	if m.state == StateLoading {
+		place := m.selectedPlace
+		client := m.client(place)
+		cmd := func() tea.Msg {
+			weather, err := client.GetWeather(place.Latitude, place.Longitude)
+			return weatherResultMsg{data: weather, err: err}
+		}
+		cmds = append(cmds, tea.Batch(cmd, m.spinner.Tick))
+	}
+
+	return tea.Sequence(cmds...)
 }
 
 // Update handles state transitions based on messages
@@ -113,6 +171,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m.handleWeatherResultMsg(msg.data)
+	case rankingResultMsg:
+		m.state = StateRanking
+		m.rankingModel = ui.NewRankingModel(msg.entries, msg.profile, m.width, m.height)
+		return m, m.rankingModel.Init()
+	case verificationResultMsg:
+		if m.state == StateWeather && msg.place == m.selectedPlace {
+			m.weatherModel.SetVerification(msg.results)
+		}
+		return m, nil
+	case modelsResultMsg:
+		if msg.err != nil {
+			m.state = StateWeather
+			m.weatherModel.SetError(msg.err)
+			return m, nil
+		}
+		m.state = StateModels
+		m.modelsModel = ui.NewModelsModel(msg.comparison, m.selectedPlace, m.cfg, m.width, m.height)
+		return m, m.modelsModel.Init()
 	case tea.WindowSizeMsg:
 		return m.handleWindowSizeMsg(msg)
 	}
@@ -126,9 +202,7 @@ func (m Model) View() string {
 		return ui.RenderError(m.err, m.width, m.height)
 	}
 
-	m.keyMap.SetState(m.state)
-	m.help.ShowAll = m.state == StateWeather
-	helpView := m.help.View(m.keyMap)
+	helpView := m.helpView()
 
 	switch m.state {
 	case StatePlace:
@@ -139,18 +213,36 @@ func (m Model) View() string {
 		return ui.RenderResults(m.placesList, helpView, m.width, m.height)
 	case StateWeather:
 		return m.weatherModel.View(helpView)
+	case StateRanking:
+		return m.rankingModel.View(helpView)
+	case StateModels:
+		return m.modelsModel.View(helpView)
 	default:
 		return ui.RenderLoading(m.spinner.View(), m.width, m.height)
 	}
 }
 
+// helpView renders the key bindings of the current state, all of them in the weather view
+func (m Model) helpView() string {
+	m.keyMap.SetState(m.state)
+	m.help.ShowAll = m.state == StateWeather
+	return m.help.View(m.keyMap)
+}
+
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.state == StateWeather {
+		m.weatherModel.SetError(nil)
+	}
+
 	switch {
 	case key.Matches(msg, m.keyMap.Quit):
 		return m, tea.Quit
 	case key.Matches(msg, m.keyMap.Back):
-		if m.state == StateResults || m.state == StateWeather {
+		switch m.state {
+		case StateResults, StateWeather, StateRanking:
 			m.state = StatePlace
+		case StateModels:
+			m.state = StateWeather
 		}
 		return m, nil
 	case key.Matches(msg, m.keyMap.Enter):
@@ -159,6 +251,32 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleAddFavorite()
 	case key.Matches(msg, m.keyMap.RemoveFavorite):
 		return m.handleRemoveFavorite()
+	case key.Matches(msg, m.keyMap.Rank):
+		return m.handleRank()
+	case key.Matches(msg, m.keyMap.Profile):
+		if m.state == StateWeather {
+			m.weatherModel.CycleProfile()
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.Clock):
+		if m.state == StateWeather {
+			m.weatherModel.CycleClock()
+		}
+		return m, nil
+	case key.Matches(msg, m.keyMap.Models):
+		return m.handleModels()
+	case m.state == StateWeather && key.Matches(msg, m.keyMap.PrevNight):
+		m.weatherModel.ShiftNight(-1)
+		return m, nil
+	case m.state == StateWeather && key.Matches(msg, m.keyMap.NextNight):
+		m.weatherModel.ShiftNight(1)
+		return m, nil
+	case m.state == StateWeather && key.Matches(msg, m.keyMap.ScrollUp):
+		m.weatherModel.Scroll(-1, msg.String() == "pgup", m.helpView())
+		return m, nil
+	case m.state == StateWeather && key.Matches(msg, m.keyMap.ScrollDown):
+		m.weatherModel.Scroll(1, msg.String() == "pgdown", m.helpView())
+		return m, nil
 	}
 
 	return m.updateActiveComponent(msg)
@@ -185,8 +303,9 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 			if place, ok := m.placeModel.GetSelectedFavorite(); ok {
 				m.selectedPlace = place
 				m.state = StateLoading
+				client := m.client(place)
 				cmd := func() tea.Msg {
-					weather, err := openmeteo.GetWeather(place.Latitude, place.Longitude)
+					weather, err := client.GetWeather(place.Latitude, place.Longitude)
 					time.Sleep(500 * time.Millisecond)
 					return weatherResultMsg{data: weather, err: err}
 				}
@@ -197,8 +316,20 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 		if i, ok := m.placesList.SelectedItem().(domain.Place); ok {
 			m.selectedPlace = i
 			m.state = StateLoading
+			client := m.client(i)
+			cmd := func() tea.Msg {
+				weather, err := client.GetWeather(i.Latitude, i.Longitude)
+				return weatherResultMsg{data: weather, err: err}
+			}
+			return m, tea.Batch(cmd, m.spinner.Tick)
+		}
+	case StateRanking:
+		if place, ok := m.rankingModel.GetSelectedPlace(); ok {
+			m.selectedPlace = place
+			m.state = StateLoading
+			client := m.client(place)
 			cmd := func() tea.Msg {
-				weather, err := openmeteo.GetWeather(i.Latitude, i.Longitude)
+				weather, err := client.GetWeather(place.Latitude, place.Longitude)
 				return weatherResultMsg{data: weather, err: err}
 			}
 			return m, tea.Batch(cmd, m.spinner.Tick)
@@ -207,6 +338,42 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleRank() (tea.Model, tea.Cmd) {
+	if m.state != StatePlace || len(m.favorites.Favorites) == 0 {
+		return m, nil
+	}
+
+	// Every favorite is ranked with the configured profile, see ranking.RankPlaces
+	profile, err := m.cfg.ResolveProfile("", domain.Place{})
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	places := m.favorites.Favorites
+	client := m.recording
+	m.state = StateLoading
+	cmd := func() tea.Msg {
+		return rankingResultMsg{entries: ranking.RankPlaces(client, places, "", profile), profile: profile.Name}
+	}
+	return m, tea.Batch(cmd, m.spinner.Tick)
+}
+
+func (m Model) handleModels() (tea.Model, tea.Cmd) {
+	if m.state != StateWeather {
+		return m, nil
+	}
+
+	place := m.selectedPlace
+	client := m.client(place)
+	m.state = StateLoading
+	cmd := func() tea.Msg {
+		comparison, err := client.GetModelComparison(place.Latitude, place.Longitude)
+		return modelsResultMsg{comparison: comparison, err: err}
+	}
+	return m, tea.Batch(cmd, m.spinner.Tick)
+}
+
 func (m Model) handleAddFavorite() (tea.Model, tea.Cmd) {
 	if m.state == StateWeather && !m.favorites.IsFavorite(m.selectedPlace) {
 		if err := m.favorites.AddFavorite(m.selectedPlace); err != nil {
@@ -215,6 +382,7 @@ func (m Model) handleAddFavorite() (tea.Model, tea.Cmd) {
 		}
 		m.keyMap.UpdateAddRemoveFavoriteBindings(true)
 		m.placeModel.UpdateFavorites()
+		m.updateRecorder()
 		return m, nil
 	}
 	return m, nil
@@ -228,11 +396,28 @@ func (m Model) handleRemoveFavorite() (tea.Model, tea.Cmd) {
 		}
 		m.keyMap.UpdateAddRemoveFavoriteBindings(false)
 		m.placeModel.UpdateFavorites()
+		m.updateRecorder()
 		return m, nil
 	}
 	return m, nil
 }
 
+// client returns the forecast client for place: the recording one when it is a favorite,
+// the default one for a searched place
+func (m Model) client(place domain.Place) *openmeteo.Client {
+	if m.favorites.IsFavorite(place) {
+		return m.recording
+	}
+	return openmeteo.DefaultClient
+}
+
+// updateRecorder records the forecasts of the favorites as they are after an edit
+func (m Model) updateRecorder() {
+	if m.recorder != nil {
+		m.recorder.SetFavorites(m.favorites.Favorites)
+	}
+}
+
 func (m Model) handleWeatherResultMsg(data domain.WeatherData) (tea.Model, tea.Cmd) {
 	m.weatherData = data
 	m.state = StateWeather
@@ -240,12 +425,36 @@ func (m Model) handleWeatherResultMsg(data domain.WeatherData) (tea.Model, tea.C
 		data,
 		m.selectedPlace,
 		m.favorites,
+		m.cfg,
 		m.width,
 		m.height,
 	)
 	isFavorite := m.favorites.IsFavorite(m.selectedPlace)
 	m.keyMap.UpdateAddRemoveFavoriteBindings(isFavorite)
-	return m, m.weatherModel.Init()
+	if !isFavorite {
+		return m, m.weatherModel.Init()
+	}
+	return m, tea.Batch(m.weatherModel.Init(), m.verifyPlace(m.selectedPlace))
+}
+
+// verifyPlace compares the past forecasts of a favorite with the observed weather in the background
+func (m Model) verifyPlace(place domain.Place) tea.Cmd {
+	cfg := m.cfg
+	return func() tea.Msg {
+		history, err := storage.NewHistoryStore()
+		if err != nil {
+			return nil
+		}
+		profile, err := cfg.ResolveProfile("", place)
+		if err != nil {
+			return nil
+		}
+		results, err := verification.VerifyPlace(place, history.PlaceRecords(place.Latitude, place.Longitude), profile.Thresholds.CloudCover, time.Now())
+		if err != nil {
+			return nil
+		}
+		return verificationResultMsg{place: place, results: results}
+	}
 }
 
 func (m Model) handleWindowSizeMsg(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
@@ -281,6 +490,14 @@ func (m Model) updateActiveComponent(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.keyMap.UpdateAddRemoveFavoriteBindings(isFavorite)
 		m.weatherModel, weatherCmd = m.weatherModel.Update(msg)
 		return m, weatherCmd
+	case StateRanking:
+		var rankingCmd tea.Cmd
+		m.rankingModel, rankingCmd = m.rankingModel.Update(msg)
+		return m, rankingCmd
+	case StateModels:
+		var modelsCmd tea.Cmd
+		m.modelsModel, modelsCmd = m.modelsModel.Update(msg)
+		return m, modelsCmd
 	}
 	return m, nil
 }
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/util"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ModelsModel shows the cloud cover of several weather models side by side
+type ModelsModel struct {
+	width, height  int
+	placeName      string
+	nightAgreement int
+	table          table.Model
+}
+
+// NewModelsModel creates the model comparison view of a place
+func NewModelsModel(comparison domain.ModelComparison, place domain.Place, cfg config.Config, width, height int) ModelsModel {
+	hours := forecast.CompareModels(comparison)
+	now := time.Now()
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, now.In(comparison.Location()))
+
+	columns := []table.Column{{Title: i18n.T("forecast.hour", nil), Width: 7}}
+	for _, series := range comparison.Models {
+		columns = append(columns, table.Column{Title: series.Model, Width: max(7, len(series.Model))})
+	}
+	columns = append(columns,
+		table.Column{Title: i18n.T("models.mean", nil), Width: 7},
+		table.Column{Title: i18n.T("models.agreement", nil), Width: 9},
+	)
+
+	var rows []table.Row
+	for _, hour := range hours {
+		if len(rows) >= cfg.Forecast.TableHours {
+			break
+		}
+		if !hour.DateTime.After(now) {
+			continue
+		}
+
+		row := table.Row{hour.DateTime.Format("15h")}
+		for _, clouds := range hour.CloudCover {
+			row = append(row, format.Percent(clouds))
+		}
+		row = append(row, fmt.Sprintf("%.0f%%", hour.Mean), format.Percent(hour.Agreement))
+		rows = append(rows, row)
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(min(len(rows)+1, max(height-10, 5))),
+	)
+
+	return ModelsModel{
+		width:          width,
+		height:         height,
+		placeName:      place.Name,
+		nightAgreement: forecast.NightModelAgreement(hours, sunInfo),
+		table:          t,
+	}
+}
+
+// Init initializes the models model
+func (m ModelsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the models model
+func (m ModelsModel) Update(msg tea.Msg) (ModelsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the model comparison table
+func (m ModelsModel) View(helpView string) string {
+	title := util.TitleStyle.Render(i18n.T("models.title", map[string]any{"Place": m.placeName}))
+
+	agreement := i18n.T("models.no_agreement", nil)
+	if m.nightAgreement >= 0 {
+		agreement = i18n.T("models.night_agreement", map[string]any{"Agreement": m.nightAgreement})
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		agreement,
+		util.TableStyle.Render(m.table.View()),
+		"",
+		helpView,
+	)
+
+	return util.BorderStyle.
+		Width(m.width-2).
+		Height(m.height-2).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(content)
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/ranking"
+	"driffaud.fr/odin/internal/util"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RankingModel shows tonight's conditions across all favorites, best first
+type RankingModel struct {
+	width, height int
+	entries       []ranking.Entry
+	profile       string
+	table         table.Model
+}
+
+// NewRankingModel creates a ranking view from already sorted entries, all analyzed with the
+// named profile
+func NewRankingModel(entries []ranking.Entry, profile string, width, height int) RankingModel {
+	columns := []table.Column{
+		{Title: "#", Width: 3},
+		{Title: i18n.T("ranking.place", nil), Width: 24},
+		{Title: i18n.T("ranking.best_window", nil), Width: 14},
+		{Title: i18n.T("forecast.clouds", nil), Width: 7},
+		{Title: i18n.T("forecast.seeing", nil), Width: 7},
+		{Title: i18n.T("forecast.rain", nil), Width: 7},
+	}
+
+	rows := make([]table.Row, len(entries))
+	for i, entry := range entries {
+		rows[i] = rankingRow(i+1, entry)
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(true),
+		table.WithHeight(len(rows)+1),
+	)
+
+	return RankingModel{
+		width:   width,
+		height:  height,
+		entries: entries,
+		profile: profile,
+		table:   t,
+	}
+}
+
+// rankingRow formats a ranking entry as a table row
+func rankingRow(position int, entry ranking.Entry) table.Row {
+	if entry.Err != nil {
+		return table.Row{
+			fmt.Sprintf("%d", position),
+			entry.Place.Name,
+			i18n.T("ranking.unavailable", nil),
+			"-",
+			"-",
+			"-",
+		}
+	}
+
+	window := "-"
+	if best, ok := entry.Night.BestWindow(); ok {
+		window = fmt.Sprintf("%s (%dh)", format.Period(best.Start, best.End, best.Start.Location()), best.Hours())
+	}
+
+	return table.Row{
+		fmt.Sprintf("%d", position),
+		entry.Place.Name,
+		window,
+		fmt.Sprintf("%d%%", entry.Night.DisplayCloudCover),
+		fmt.Sprintf("%d/5", entry.Night.SeeingIndex),
+		fmt.Sprintf("%d%%", entry.Night.MaxPrecipProbability),
+	}
+}
+
+// Init initializes the ranking model
+func (m RankingModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the ranking model
+func (m RankingModel) Update(msg tea.Msg) (RankingModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// View renders the ranking table
+func (m RankingModel) View(helpView string) string {
+	title := util.TitleStyle.Render(i18n.T("ranking.title", nil))
+
+	var body string
+	if len(m.entries) == 0 {
+		body = lipgloss.NewStyle().
+			Faint(true).
+			Render(i18n.T("app.no_favorites", nil))
+	} else {
+		body = lipgloss.JoinVertical(
+			lipgloss.Center,
+			i18n.T("ranking.profile", map[string]any{"Profile": m.profile}),
+			util.TableStyle.Render(m.table.View()),
+		)
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		"",
+		body,
+		"",
+		helpView,
+	)
+
+	return util.BorderStyle.
+		Width(m.width-2).
+		Height(m.height-2).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(content)
+}
+
+// GetSelectedPlace returns the place of the highlighted row if any
+func (m RankingModel) GetSelectedPlace() (domain.Place, bool) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.entries) {
+		return domain.Place{}, false
+	}
+	return m.entries[cursor].Place, true
+}
@@ -2,35 +2,82 @@ package ui
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"driffaud.fr/odin/internal/config"
 	"driffaud.fr/odin/internal/domain"
 	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/domain/deepsky"
+	"driffaud.fr/odin/internal/domain/planets"
 	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/format"
 	"driffaud.fr/odin/internal/i18n"
 	"driffaud.fr/odin/internal/platform/storage"
 	"driffaud.fr/odin/internal/util"
+	"driffaud.fr/odin/internal/verification"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// maxDisplayedWindows is the number of observation windows listed for tonight
+const maxDisplayedWindows = 3
+
 // WeatherModel represents the weather view component
 type WeatherModel struct {
+	cfg           config.Config
 	width, height int
 	weatherData   domain.WeatherData
 	placeName     string
 	isFavorite    bool
 	favorites     *storage.FavoritesStore
 	selectedPlace domain.Place
+	profile       forecast.Profile
+	verification  []verification.Result
+	verified      bool
+	clock         Clock
+	date          time.Time // day on which the night shown starts, zero for tonight
+	analysis      nightAnalysis
+	scroll        int   // first line of the content shown when it does not fit the screen
+	err           error // failure of the last action started from the view, shown below the header
+}
+
+// nightAnalysis holds the forecast analysis shown by the view. It is computed when the data,
+// the profile or the night change rather than on every render.
+type nightAnalysis struct {
+	forecastData  []forecast.ForecastHour
+	outlook       []forecast.NightOutlook
+	sunInfo       astro.SunInfo
+	moonInfo      astro.MoonInfo
+	nightForecast forecast.NightForecast
+	forecasted    bool // whether the forecast covers the night shown
+	planets       []planets.Visibility
+	targets       []deepsky.Target
 }
 
+// Clock selects the time zone in which the weather view shows times
+type Clock int
+
+const (
+	ClockSite  Clock = iota // time zone of the observing site
+	ClockLocal              // time zone of this computer
+	ClockUTC
+)
+
 // NewWeatherModel creates a new weather view model
-func NewWeatherModel(data domain.WeatherData, place domain.Place, favorites *storage.FavoritesStore, width, height int) WeatherModel {
+func NewWeatherModel(data domain.WeatherData, place domain.Place, favorites *storage.FavoritesStore, cfg config.Config, width, height int) WeatherModel {
 	isFavorite := favorites.IsFavorite(place)
 	placeName := place.Name + " (" + place.Address + ")"
 
-	return WeatherModel{
+	profile, err := cfg.ResolveProfile("", place)
+	if err != nil {
+		profile = forecast.DefaultProfile()
+	}
+
+	m := WeatherModel{
+		cfg:           cfg,
 		width:         width,
 		height:        height,
 		weatherData:   data,
@@ -38,7 +85,116 @@ func NewWeatherModel(data domain.WeatherData, place domain.Place, favorites *sto
 		isFavorite:    isFavorite,
 		favorites:     favorites,
 		selectedPlace: place,
+		profile:       profile,
 	}
+	m.analyze()
+	return m
+}
+
+// analyze runs the forecast analysis of the whole horizon with the current profile,
+// then that of the night shown
+func (m *WeatherModel) analyze() {
+	lat, lon := m.weatherData.Latitude, m.weatherData.Longitude
+	m.analysis.forecastData = forecast.GenerateForecastDataWithProfile(m.weatherData, m.profile)
+	m.analysis.outlook = forecast.AnalyzeNights(m.analysis.forecastData, lat, lon, m.profile.Thresholds)
+	m.analyzeNight()
+}
+
+// analyzeNight runs the analysis of the night shown on the forecast data already generated
+func (m *WeatherModel) analyzeNight() {
+	lat, lon := m.weatherData.Latitude, m.weatherData.Longitude
+	at := m.observerTime()
+
+	m.analysis.sunInfo = astro.GetSunInfo(lat, lon, at)
+	m.analysis.moonInfo = astro.GetMoonInfo(lat, lon, at)
+	m.analysis.nightForecast = forecast.AnalyzeNight(m.analysis.forecastData, lat, lon, m.analysis.sunInfo, m.profile.Thresholds)
+	m.analysis.forecasted = forecast.CoversNight(m.analysis.forecastData, m.analysis.sunInfo)
+	m.analysis.planets = planets.GetVisibility(lat, lon, m.analysis.sunInfo.Night())
+
+	windows := m.analysis.nightForecast.DarkWindows
+	if m.analysis.forecasted {
+		windows = m.analysis.nightForecast.ClearIntervals()
+	}
+	m.analysis.targets = deepsky.BestTargets(lat, lon, windows, m.profile.Thresholds.MinTargetAltitude, deepsky.DefaultCount)
+}
+
+// CycleProfile switches the analysis to the next scoring profile for this session
+func (m *WeatherModel) CycleProfile() {
+	profile := forecast.NextProfile(m.profile.Name)
+	if resolved, err := m.cfg.ResolveProfile(profile.Name, m.selectedPlace); err == nil {
+		profile = resolved
+	}
+	m.profile = profile
+	m.analyze()
+}
+
+// CycleClock switches the times between the site's time zone, the local one and UTC
+func (m *WeatherModel) CycleClock() {
+	m.clock = (m.clock + 1) % 3
+}
+
+// ShiftNight moves the night shown by days, never before tonight.
+// Nights beyond the forecast horizon only show the sun and moon.
+func (m *WeatherModel) ShiftNight(days int) {
+	year, month, day := time.Now().In(m.weatherData.Location()).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	date := m.date
+	if date.IsZero() {
+		date = today
+	}
+	date = date.AddDate(0, 0, days)
+
+	if date.After(today) {
+		m.date = date
+	} else {
+		m.date = time.Time{}
+	}
+	m.analyzeNight()
+}
+
+// Scroll moves the content shown by lines, or by pages when page is set, when it does not
+// fit the screen above the help
+func (m *WeatherModel) Scroll(lines int, page bool, helpView string) {
+	available := m.contentHeight(helpView)
+	if page {
+		lines *= max(available-1, 1)
+	}
+	maxScroll := max(lipgloss.Height(m.content())-available, 0)
+	m.scroll = min(max(min(m.scroll, maxScroll)+lines, 0), maxScroll)
+}
+
+// contentHeight returns the number of lines available for the content above the help
+func (m WeatherModel) contentHeight(helpView string) int {
+	return max(m.height-2-lipgloss.Height(helpView), 1)
+}
+
+// SetError shows err below the header until it is cleared with a nil error
+func (m *WeatherModel) SetError(err error) {
+	m.err = err
+}
+
+// observerTime returns the time at which the night shown is analyzed
+func (m WeatherModel) observerTime() time.Time {
+	return astro.ObserverTime(m.date, m.weatherData.Location(), time.Now())
+}
+
+// location returns the time zone in which times are shown
+func (m WeatherModel) location() *time.Location {
+	switch m.clock {
+	case ClockLocal:
+		return time.Local
+	case ClockUTC:
+		return time.UTC
+	default:
+		return m.weatherData.Location()
+	}
+}
+
+// SetVerification sets the forecast track record of the place, shown below the outlook
+func (m *WeatherModel) SetVerification(results []verification.Result) {
+	m.verification = results
+	m.verified = true
 }
 
 // Init initializes the weather model
@@ -68,28 +224,48 @@ func (m WeatherModel) View(helpView string) string {
 			Render(i18n.T("weather.no_data", nil))
 	}
 
-	forecastData := forecast.GenerateForecastData(m.weatherData)
+	content := m.content()
+	if lines := strings.Split(content, "\n"); len(lines) > m.contentHeight(helpView) {
+		available := m.contentHeight(helpView)
+		first := min(m.scroll, len(lines)-available)
+		content = strings.Join(lines[first:first+available], "\n")
+	}
+
+	return util.BorderStyle.
+		Width(m.width-2).
+		Height(m.height-2).
+		Padding(0, 1).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, content, helpView))
+}
 
-	astroSection := formatAstroInfo(forecastData, m.weatherData.Latitude, m.weatherData.Longitude)
+// content renders the header and the sections of the weather display
+func (m WeatherModel) content() string {
+	astroSection := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.formatAstroInfo(),
+			m.formatTargets(),
+		),
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.formatOutlook(),
+			m.formatPlanets(),
+			m.formatVerification(),
+		),
+	)
 	var forecastSection string
-	if len(m.weatherData.Hourly.Time) >= 24 {
-		forecastSection = formatForecast(forecastData)
+	if len(m.weatherData.Hourly.Time) >= m.cfg.Forecast.TableHours {
+		forecastSection = m.formatForecast()
 	}
 
-	content := lipgloss.JoinVertical(
+	return lipgloss.JoinVertical(
 		lipgloss.Center,
 		m.headerView(),
 		astroSection,
 		forecastSection,
-		helpView,
 	)
-
-	return util.BorderStyle.
-		Width(m.width-2).
-		Height(m.height-2).
-		Padding(0, 1).
-		Align(lipgloss.Center, lipgloss.Center).
-		Render(content)
 }
 
 func (m WeatherModel) headerView() string {
@@ -105,71 +281,88 @@ func (m WeatherModel) headerView() string {
 		"FavStatus": favoriteStatus,
 	}))
 
-	return util.TitleStyle.Render(title)
+	profile := i18n.T("weather.profile", map[string]any{
+		"Profile": m.profile.Name,
+	})
+
+	lines := []string{util.TitleStyle.Render(title), profile, m.nightView(), m.clockView()}
+	if m.err != nil {
+		lines = append(lines, util.ErrorStyle.Render(i18n.T("weather.error", map[string]any{"Error": m.err})))
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, lines...)
+}
+
+// nightView tells which night is shown
+func (m WeatherModel) nightView() string {
+	if m.date.IsZero() {
+		return i18n.T("weather.tonight", nil)
+	}
+	return i18n.T("weather.night_of", map[string]any{
+		"Weekday": i18n.Weekday(m.date.Weekday()),
+		"Date":    m.date.Format(time.DateOnly),
+	})
 }
 
-func formatTime(t time.Time) string {
-	return t.Format("15:04")
+// clockView tells in which time zone the times are shown
+func (m WeatherModel) clockView() string {
+	zone := time.Now().In(m.location()).Format("MST")
+	switch m.clock {
+	case ClockLocal:
+		return i18n.T("clock.local", map[string]any{"Zone": zone})
+	case ClockUTC:
+		return i18n.T("clock.utc", nil)
+	default:
+		name := m.weatherData.Timezone
+		if name == "" {
+			name = zone
+		}
+		return i18n.T("clock.site", map[string]any{"Zone": name, "Abbreviation": zone})
+	}
 }
 
-func formatAstroInfo(forecastData []forecast.ForecastHour, lat, lon float64) string {
-	sunInfo := astro.GetSunInfo(lat, lon)
-	moonInfo := astro.GetMoonInfo(lat, lon)
-	nightForecast := forecast.AnalyzeNightForecast(forecastData, sunInfo.Sunset, sunInfo.Sunrise)
+func (m WeatherModel) formatAstroInfo() string {
+	sunInfo, moonInfo, nightForecast := m.analysis.sunInfo, m.analysis.moonInfo, m.analysis.nightForecast
+	loc := m.location()
 
-	sunInfoStr := fmt.Sprint(i18n.T("weather.sunset", map[string]any{
-		"Sunset":  formatTime(sunInfo.Sunset),
-		"Dusk":    formatTime(sunInfo.Dusk),
-		"Dawn":    formatTime(sunInfo.Dawn),
-		"Sunrise": formatTime(sunInfo.Sunrise),
-	}),
-	)
+	sunInfoStr := format.Sun(sunInfo.Sunset, sunInfo.Dusk, sunInfo.Dawn, sunInfo.Sunrise, loc)
+	if darkness := format.Darkness(sunInfo.Darkness.String(), sunInfo.DarkestTime, sunInfo.DarkestAltitude, sunInfo.PolarNight, loc); darkness != "" {
+		sunInfoStr = lipgloss.JoinVertical(lipgloss.Left, sunInfoStr, darkness)
+	}
 
-	moonInfoStr := fmt.Sprint(i18n.T("weather.moonphase", map[string]any{
-		"MoonEmoji":    moonInfo.PhaseEmoji,
-		"Moonrise":     formatTime(moonInfo.Moonrise),
-		"Moonset":      formatTime(moonInfo.Moonset),
-		"Illumination": fmt.Sprintf("%.0f", moonInfo.Illumination),
-		"PhaseName":    moonInfo.PhaseName,
-	}),
-	)
+	moonInfoStr := format.Moon(moonInfo.PhaseEmoji, moonInfo.PhaseName, moonInfo.Illumination, moonInfo.Moonrise, moonInfo.Moonset, loc)
 
 	forecastTitle := i18n.T("weather.conditions_title", nil)
 
 	var observationTimeStr string
-	if nightForecast.BestObservation.TimeRange != nil {
-		observationTimeStr = fmt.Sprint(i18n.T("weather.best_period", map[string]any{
-			"Start":      nightForecast.BestObservation.TimeRange.Start,
-			"End":        nightForecast.BestObservation.TimeRange.End,
-			"CloudCover": nightForecast.BestObservation.LowestCloudCover,
-		}))
+	if len(nightForecast.Windows) > 0 {
+		lines := []string{i18n.T("weather.best_windows", nil)}
+		for _, window := range nightForecast.Windows[:min(len(nightForecast.Windows), maxDisplayedWindows)] {
+			lines = append(lines, "  "+format.Window(window.Start, window.End, window.Hours(), window.CloudCover, window.Seeing, window.Rating, window.Confidence, loc))
+		}
+		observationTimeStr = lipgloss.JoinVertical(lipgloss.Left, lines...)
 	} else {
 		observationTimeStr = fmt.Sprint(i18n.T("weather.unfavorable", map[string]any{
 			"CloudCover": nightForecast.DisplayCloudCover,
 		}))
 	}
 
-	weatherConditions := fmt.Sprint(i18n.T("weather.conditions", map[string]any{
-		"Temp":      nightForecast.NightlyTemperature,
-		"Humidity":  nightForecast.NightlyHumidity,
-		"WindSpeed": nightForecast.NightlyWindSpeed,
-		"WindDir":   nightForecast.WindDirectionText,
-		"DewPoint":  nightForecast.NightlyDewPoint,
-	}))
-
-	precipAndSeeing := fmt.Sprint(i18n.T("weather.precip_and_seeing", map[string]any{
-		"Precip": nightForecast.MaxPrecipProbability,
-		"Seeing": nightForecast.SeeingIndex,
-	}))
-
 	nightForecastStr := lipgloss.JoinVertical(
 		lipgloss.Left,
 		forecastTitle,
+		format.DarkWindows(nightForecast.DarkWindows, sunInfo.Darkness.String(), loc),
 		observationTimeStr,
-		weatherConditions,
-		precipAndSeeing,
+		format.Conditions(m.cfg.Units, float64(nightForecast.NightlyTemperature), nightForecast.NightlyHumidity, float64(nightForecast.NightlyWindSpeed), nightForecast.WindDirectionText, float64(nightForecast.NightlyDewPoint)),
+		format.PrecipitationAndSeeing(nightForecast.MaxPrecipProbability, nightForecast.SeeingIndex),
+		format.Transparency(nightForecast.TransparencyIndex),
+		format.Dew(nightForecast.DewRisk.String(), nightForecast.DewStart, nightForecast.DewHeaterPower, loc),
+		format.Gusts(m.cfg.Units, float64(nightForecast.MaxWindGusts), nightForecast.ShakeRisk.String()),
+		format.Fog(nightForecast.FogStart, nightForecast.MaxFogProbability, loc),
 	)
 
+	if !m.analysis.forecasted {
+		nightForecastStr = lipgloss.JoinVertical(lipgloss.Left, forecastTitle, i18n.T("weather.beyond_horizon", nil))
+	}
+
 	return util.AstroInfoStyle.Render(lipgloss.JoinVertical(
 		lipgloss.Left,
 		sunInfoStr,
@@ -180,34 +373,253 @@ func formatAstroInfo(forecastData []forecast.ForecastHour, lat, lon float64) str
 	))
 }
 
-func formatForecast(forecastData []forecast.ForecastHour) string {
+// formatOutlook renders a grid summarizing every night of the forecast horizon
+func (m WeatherModel) formatOutlook() string {
+	nights := m.analysis.outlook
+	if len(nights) == 0 {
+		return ""
+	}
+
+	title := util.SubtitleStyle.Render(i18n.T("outlook.title", nil))
+
+	columns := []table.Column{
+		{Title: i18n.T("outlook.night", nil), Width: 8},
+		{Title: i18n.T("outlook.rating", nil), Width: 6},
+		{Title: i18n.T("outlook.window", nil), Width: 12},
+		{Title: i18n.T("outlook.clouds", nil), Width: 7},
+		{Title: i18n.T("outlook.moon", nil), Width: 7},
+	}
+
+	rows := make([]table.Row, len(nights))
+	for i, night := range nights {
+		window := i18n.T("outlook.no_window", nil)
+		if best, ok := night.Night.BestWindow(); ok {
+			window = format.Period(best.Start, best.End, m.location())
+		}
+
+		rows[i] = table.Row{
+			fmt.Sprintf("%s %d", i18n.Weekday(night.Date.Weekday()), night.Date.Day()),
+			fmt.Sprintf("%d/5", night.Night.Rating),
+			window,
+			fmt.Sprintf("%d%%", night.Night.DisplayCloudCover),
+			fmt.Sprintf("%s %.0f%%", night.Moon.PhaseEmoji, night.Moon.Illumination),
+		}
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(false),
+		table.WithHeight(len(rows)+1),
+	)
+
+	return util.OutlookStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		util.TableStyle.Render(t.View()),
+	))
+}
+
+// formatPlanets lists when the planets rise, transit and set, how they look and when
+// they can be observed within the clear windows of the night
+func (m WeatherModel) formatPlanets() string {
+	nightForecast := m.analysis.nightForecast
+	title := util.SubtitleStyle.Render(i18n.T("planets.title", nil))
+	loc := m.location()
+
+	columns := []table.Column{
+		{Title: i18n.T("planets.planet", nil), Width: 8},
+		{Title: i18n.T("planets.rise", nil), Width: 6},
+		{Title: i18n.T("planets.transit", nil), Width: 7},
+		{Title: i18n.T("planets.set", nil), Width: 6},
+		{Title: i18n.T("planets.max_altitude", nil), Width: 5},
+		{Title: i18n.T("planets.magnitude", nil), Width: 5},
+		{Title: i18n.T("planets.diameter", nil), Width: 6},
+		{Title: i18n.T("planets.elongation", nil), Width: 6},
+		{Title: i18n.T("planets.clear", nil), Width: 15},
+	}
+
+	var windows []astro.Interval
+	if m.analysis.forecasted {
+		windows = nightForecast.ClearIntervals()
+	}
+
+	visibilities := m.analysis.planets
+	rows := make([]table.Row, len(visibilities))
+	for i, visibility := range visibilities {
+		rows[i] = table.Row{
+			i18n.T("planets."+visibility.Planet.String(), nil),
+			format.Time(visibility.Rise, loc),
+			format.Time(visibility.Transit, loc),
+			format.Time(visibility.Set, loc),
+			fmt.Sprintf("%.0f°", visibility.MaxAltitude),
+			fmt.Sprintf("%.1f", visibility.Magnitude),
+			fmt.Sprintf("%.0f\"", visibility.Diameter),
+			format.Elongation(visibility.Elongation),
+			format.PlanetClear(len(visibility.Observable) > 0, visibility.Clear(windows), windows != nil, loc),
+		}
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(false),
+		table.WithHeight(len(rows)+1),
+	)
+
+	return util.OutlookStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		util.TableStyle.Render(t.View()),
+	))
+}
+
+// formatTargets lists the deep-sky objects best placed during the clear windows of the night,
+// or during its dark windows when the night is not forecast yet
+func (m WeatherModel) formatTargets() string {
+	title := util.SubtitleStyle.Render(i18n.T("targets.title", nil))
+	lines := []string{title}
+	if !m.analysis.forecasted {
+		lines = append(lines, i18n.T("targets.dark_only", nil))
+	}
+
+	targets := m.analysis.targets
+	if len(targets) == 0 {
+		lines = append(lines, i18n.T("targets.none", nil))
+		return util.TargetsStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	}
+
+	columns := []table.Column{
+		{Title: i18n.T("targets.object", nil), Width: 6},
+		{Title: i18n.T("targets.name", nil), Width: 20},
+		{Title: i18n.T("targets.type", nil), Width: 16},
+		{Title: i18n.T("targets.magnitude", nil), Width: 5},
+		{Title: i18n.T("targets.up", nil), Width: 6},
+		{Title: i18n.T("targets.transit", nil), Width: 11},
+		{Title: i18n.T("targets.moon", nil), Width: 5},
+	}
+
+	rows := make([]table.Row, len(targets))
+	for i, target := range targets {
+		magnitude := "–"
+		if !math.IsNaN(target.Magnitude) {
+			magnitude = fmt.Sprintf("%.1f", target.Magnitude)
+		}
+		moon := "–"
+		if target.MoonUp {
+			moon = fmt.Sprintf("%.0f°", target.MoonSeparation)
+		}
+
+		rows[i] = table.Row{
+			target.ID,
+			target.Label(),
+			i18n.T("targets."+string(target.Type), nil),
+			magnitude,
+			fmt.Sprintf("%.1f h", target.Hours()),
+			fmt.Sprintf("%s %.0f°", format.Time(target.Transit, m.location()), target.TransitAltitude),
+			moon,
+		}
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows),
+		table.WithFocused(false),
+		table.WithHeight(len(rows)+1),
+	)
+
+	lines = append(lines, util.TableStyle.Render(t.View()))
+	return util.TargetsStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// formatVerification summarizes how the forecast model fared at this place in the past
+func (m WeatherModel) formatVerification() string {
+	if !m.verified {
+		return ""
+	}
+
+	title := util.SubtitleStyle.Render(i18n.T("verify.title", nil))
+	model := m.cfg.API.Models
+	stats := verification.Summarize(m.verification, model)
+	if stats.Hours == 0 {
+		return util.OutlookStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, i18n.T("verify.no_data", nil)))
+	}
+
+	summary := i18n.T("verify.summary", map[string]any{
+		"Model":    model,
+		"Hours":    stats.Hours,
+		"CloudMAE": fmt.Sprintf("%.0f", stats.CloudMAE),
+		"HitRate":  fmt.Sprintf("%.0f", stats.ClearHitRate),
+	})
+	if stats.TemperatureHours > 0 {
+		summary += ", " + i18n.T("verify.temperature", map[string]any{
+			"TemperatureMAE": strings.TrimPrefix(m.cfg.Units.TemperatureDifference(stats.TemperatureMAE, 1), "+"),
+		})
+	}
+
+	var leads []string
+	for _, result := range m.verification {
+		if result.Model == model && result.Hours > 0 {
+			leads = append(leads, fmt.Sprintf("%s %.0f%%", i18n.T("verify.lead_day", map[string]any{"Day": result.LeadDay}), result.ClearHitRate))
+		}
+	}
+
+	return util.OutlookStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		summary,
+		i18n.T("verify.by_lead", map[string]any{"Leads": strings.Join(leads, " · ")}),
+	))
+}
+
+func (m WeatherModel) formatForecast() string {
+	forecastData := m.analysis.forecastData
 	title := util.SubtitleStyle.Render(i18n.T("forecast.title", nil))
 
-	now := time.Now()
-	startIndex := 0
+	at := m.observerTime()
+	startIndex := len(forecastData)
 
 	for i, hour := range forecastData {
-		if hour.DateTime.After(now) {
+		if hour.DateTime.After(at) {
 			startIndex = i
 			break
 		}
 	}
 
-	hoursToShow := 24
+	hoursToShow := m.cfg.Forecast.TableHours
 	if startIndex+hoursToShow > len(forecastData) {
 		hoursToShow = len(forecastData) - startIndex
 	}
+	if hoursToShow == 0 {
+		return ""
+	}
+
+	showClearProbability := m.cfg.Forecast.ShowClearProbability
+	showCloudLayers := m.cfg.Forecast.ShowCloudLayers
 
 	columns := []table.Column{
 		{Title: i18n.T("forecast.hour", nil), Width: 7},
 		{Title: i18n.T("forecast.clouds", nil), Width: 7},
+	}
+	if showCloudLayers {
+		columns = append(columns, table.Column{Title: i18n.T("forecast.cloud_layers", nil), Width: 14})
+	}
+	if showClearProbability {
+		columns = append(columns, table.Column{Title: i18n.T("forecast.clear_probability", nil), Width: 9})
+	}
+	columns = append(columns, []table.Column{
 		{Title: i18n.T("forecast.rain", nil), Width: 7},
-		{Title: i18n.T("forecast.seeing", nil), Width: 7},
+		{Title: i18n.T("forecast.seeing", nil), Width: 9},
+		{Title: i18n.T("forecast.transparency", nil), Width: 7},
+		{Title: i18n.T("forecast.moon", nil), Width: 7},
 		{Title: i18n.T("forecast.wind", nil), Width: 9},
+		{Title: i18n.T("forecast.gusts", nil), Width: 18},
 		{Title: i18n.T("forecast.humidity", nil), Width: 9},
+		{Title: i18n.T("forecast.fog", nil), Width: 7},
 		{Title: i18n.T("forecast.temp", nil), Width: 7},
 		{Title: i18n.T("forecast.dew", nil), Width: 7},
-	}
+		{Title: i18n.T("forecast.dew_risk", nil), Width: 14},
+	}...)
 
 	var rows []table.Row
 	for i := range hoursToShow {
@@ -215,15 +627,28 @@ func formatForecast(forecastData []forecast.ForecastHour) string {
 		hour := forecastData[idx]
 
 		row := table.Row{
-			hour.DateTime.Format("15h"),
+			hour.DateTime.In(m.location()).Format("15h"),
 			fmt.Sprintf("%d%%", hour.Clouds),
+		}
+		if showCloudLayers {
+			row = append(row, format.CloudLayers(hour.CloudsLow, hour.CloudsMid, hour.CloudsHigh))
+		}
+		if showClearProbability {
+			row = append(row, format.Percent(hour.ClearProbability))
+		}
+		row = append(row,
 			fmt.Sprintf("%d%%", hour.PrecipitationProbability),
-			fmt.Sprintf("%d/5", hour.Seeing),
-			fmt.Sprintf("%.1f km/h", hour.WindSpeed),
+			format.Seeing(hour.Seeing, hour.SeeingArcsec),
+			format.TransparencyIndex(hour.Transparency),
+			format.MoonPosition(hour.Moon),
+			m.cfg.Units.WindSpeed(hour.WindSpeed, 1),
+			fmt.Sprintf("%s %s", m.cfg.Units.WindSpeed(hour.WindGusts, 0), i18n.T("shake_risk."+hour.ShakeRisk.String(), nil)),
 			fmt.Sprintf("%d%%", hour.Humidity),
-			fmt.Sprintf("%.1f°C", hour.Temperature),
-			fmt.Sprintf("%.1f°C", hour.DewPoint),
-		}
+			fmt.Sprintf("%d%%", hour.FogProbability),
+			m.cfg.Units.Temperature(hour.Temperature, 1),
+			m.cfg.Units.Temperature(hour.DewPoint, 1),
+			fmt.Sprintf("%s %d%%", i18n.T("dew_risk."+hour.DewRisk.String(), nil), hour.DewHeaterPower),
+		)
 		rows = append(rows, row)
 	}
 
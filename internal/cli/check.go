@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/util"
+)
+
+// Exit codes of the check command
+const (
+	checkGo    = 0
+	checkNoGo  = 1
+	checkError = 2
+)
+
+// checkThresholds holds the limits a night must meet to be a "go"
+type checkThresholds struct {
+	maxCloudCover int
+	minClearHours int
+	maxPrecip     int
+	minSeeing     int
+}
+
+func runCheck(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("check", "[flags] [favorite name or search query]", stderr)
+
+	var places placeFlags
+	places.register(fs)
+	var thresholds checkThresholds
+	fs.IntVar(&thresholds.maxCloudCover, "max-clouds", 0, "maximum cloud cover in percent for an hour to count as clear (default from the scoring profile)")
+	fs.IntVar(&thresholds.minClearHours, "min-hours", 0, "minimum number of consecutive clear hours (default from the scoring profile)")
+	fs.IntVar(&thresholds.maxPrecip, "max-precip", 100, "maximum precipitation probability in percent during the night")
+	fs.IntVar(&thresholds.minSeeing, "min-seeing", 1, "minimum seeing index (1-5) for the night")
+	quiet := fs.Bool("quiet", false, "do not print the reason, only set the exit status")
+	profileName := profileFlag(fs)
+	date := dateFlag(fs)
+
+	if code, ok := parseFlags(fs, args); !ok {
+		if code == 0 {
+			return 0
+		}
+		return checkError
+	}
+
+	store, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return checkError
+	}
+
+	place, err := places.resolve(cfg, store, fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return checkError
+	}
+
+	profile, err := cfg.ResolveProfile(*profileName, place)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return checkError
+	}
+	if !isFlagSet(fs, "max-clouds") {
+		thresholds.maxCloudCover = profile.Thresholds.CloudCover
+	}
+	if !isFlagSet(fs, "min-hours") {
+		thresholds.minClearHours = profile.Thresholds.MinWindowHours
+	}
+
+	if err := thresholds.validate(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return checkError
+	}
+
+	weather, err := placeClient(store, place).GetWeather(place.Latitude, place.Longitude)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return checkError
+	}
+
+	forecastData := forecast.GenerateForecastDataWithProfile(weather, profile)
+	nightDate, err := util.ParseNightDate(*date, weather.Location(), time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return checkError
+	}
+	at := astro.ObserverTime(nightDate, weather.Location(), time.Now())
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, at)
+	if !forecast.CoversNight(forecastData, sunInfo) {
+		fmt.Fprintf(stderr, "error: no forecast yet for the night of %s\n", at.Format(time.DateOnly))
+		return checkError
+	}
+	nightThresholds := profile.Thresholds
+	nightThresholds.CloudCover = thresholds.maxCloudCover
+	nightThresholds.MinWindowHours = thresholds.minClearHours
+	night := forecast.AnalyzeNight(forecastData, place.Latitude, place.Longitude, sunInfo, nightThresholds)
+
+	code, reason := evaluateNight(night, thresholds)
+	if !*quiet {
+		fmt.Fprintf(stdout, "%s: %s\n", place.Name, reason)
+	}
+	return code
+}
+
+func (t checkThresholds) validate() error {
+	if t.maxCloudCover < 0 || t.maxCloudCover > 100 {
+		return fmt.Errorf("--max-clouds must be between 0 and 100, got %d", t.maxCloudCover)
+	}
+	if t.minClearHours < 1 {
+		return fmt.Errorf("--min-hours must be at least 1, got %d", t.minClearHours)
+	}
+	if t.maxPrecip < 0 || t.maxPrecip > 100 {
+		return fmt.Errorf("--max-precip must be between 0 and 100, got %d", t.maxPrecip)
+	}
+	if t.minSeeing < 1 || t.minSeeing > 5 {
+		return fmt.Errorf("--min-seeing must be between 1 and 5, got %d", t.minSeeing)
+	}
+	return nil
+}
+
+// evaluateNight compares a night forecast with the thresholds and returns the exit code and a short reason
+func evaluateNight(night forecast.NightForecast, t checkThresholds) (int, string) {
+	var failures []string
+
+	window, ok := night.BestWindow()
+	if !ok {
+		failures = append(failures, i18n.T("check.no_window", map[string]any{
+			"Hours":      t.minClearHours,
+			"CloudCover": t.maxCloudCover,
+			"Lowest":     night.DisplayCloudCover,
+		}))
+	}
+	if night.MaxPrecipProbability > t.maxPrecip {
+		failures = append(failures, i18n.T("check.precip", map[string]any{
+			"Precip": night.MaxPrecipProbability,
+			"Max":    t.maxPrecip,
+		}))
+	}
+	if night.SeeingIndex < t.minSeeing {
+		failures = append(failures, i18n.T("check.seeing", map[string]any{
+			"Seeing": night.SeeingIndex,
+			"Min":    t.minSeeing,
+		}))
+	}
+
+	if len(failures) > 0 {
+		return checkNoGo, i18n.T("check.no_go", map[string]any{
+			"Reasons": strings.Join(failures, "; "),
+		})
+	}
+
+	return checkGo, i18n.T("check.go", map[string]any{
+		"Start":      format.Time(window.Start, window.Start.Location()),
+		"End":        format.Time(window.End, window.End.Location()),
+		"Hours":      window.Hours(),
+		"CloudCover": fmt.Sprintf("%.0f", window.CloudCover),
+		"Seeing":     night.SeeingIndex,
+	})
+}
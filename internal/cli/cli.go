@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/util"
+)
+
+// command is a non-interactive subcommand of the odin binary
+type command struct {
+	name    string
+	summary string
+	run     func(cfg config.Config, args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "forecast", summary: "print tonight's forecast for a place", run: runForecast},
+	{name: "favorites", summary: "list and edit favorite places", run: runFavorites},
+	{name: "models", summary: "compare the cloud cover of several weather models", run: runModels},
+	{name: "rank", summary: "rank favorites by tonight's conditions", run: runRank},
+	{name: "check", summary: "exit 0 if tonight is good enough to observe, 1 if not", run: runCheck},
+	{name: "verify", summary: "compare past forecasts of favorites with the observed weather", run: runVerify},
+	{name: "serve", summary: "serve the forecast as a JSON HTTP API", run: runServe},
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit code
+func Run(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(cfg, args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+	printUsage(stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: odin [global flags] [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, odin starts the interactive interface.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	globalFlagSet(&GlobalOptions{}, w).PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'odin <command> -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a command, printing its usage line on -h
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: odin %s %s\n", name, usage)
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and converts the outcome to an exit code when parsing stops the command
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0, false
+		}
+		return 2, false
+	}
+	return 0, true
+}
+
+// writeJSON encodes v as indented JSON to stdout and returns the exit code
+func writeJSON(stdout, stderr io.Writer, v any) int {
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// profileFlag adds the --profile flag to a flag set
+func profileFlag(fs *flag.FlagSet) *string {
+	return fs.String("profile", "", "scoring profile: "+strings.Join(forecast.ProfileNames(), ", ")+" (default from the favorite or the configuration)")
+}
+
+// dateFlag adds the --date flag to a flag set. Only its syntax is checked here: the day it
+// stands for, and whether it is past, depend on the time zone of the site, see
+// util.ParseNightDate. The empty value stands for tonight.
+func dateFlag(fs *flag.FlagSet) *string {
+	var date string
+	fs.Func("date", "night to analyze: YYYY-MM-DD, today, tomorrow or +N days (default tonight)", func(value string) error {
+		if _, err := util.ParseDate(value, time.Now()); err != nil {
+			return err
+		}
+		date = value
+		return nil
+	})
+	return &date
+}
+
+// isFlagSet reports whether the named flag was given on the command line
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
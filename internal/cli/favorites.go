@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/platform/api/photon"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+)
+
+// favoritesCommand is an action of the favorites subcommand
+type favoritesCommand struct {
+	name    string
+	usage   string
+	summary string
+	run     func(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int
+}
+
+var favoritesCommands = []favoritesCommand{
+	{name: "list", usage: "[--format text|json]", summary: "list all favorites", run: runFavoritesList},
+	{name: "show", usage: "[--format text|json] <name>", summary: "show a single favorite", run: runFavoritesShow},
+	{name: "add", usage: "[--name name] [--profile name] (--lat lat --lon lon | <query>)", summary: "add a favorite", run: runFavoritesAdd},
+	{name: "remove", usage: "<name>", summary: "remove a favorite", run: runFavoritesRemove},
+	{name: "rename", usage: "<old name> <new name>", summary: "rename a favorite", run: runFavoritesRename},
+	{name: "profile", usage: "<name> <profile|none>", summary: "set the scoring profile of a favorite", run: runFavoritesProfile},
+}
+
+func runFavorites(_ config.Config, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printFavoritesUsage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range favoritesCommands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		store, err := storage.NewFavoritesStore()
+		if err != nil {
+			fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+			return 1
+		}
+		return cmd.run(store, args[1:], stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "unknown favorites command %q\n\n", args[0])
+	printFavoritesUsage(stderr)
+	return 2
+}
+
+func printFavoritesUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: odin favorites <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range favoritesCommands {
+		fmt.Fprintf(w, "  %-8s %-54s %s\n", cmd.name, cmd.usage, cmd.summary)
+	}
+}
+
+func runFavoritesList(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites list", "[--format text|json]", stderr)
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	switch *format {
+	case "json":
+		reports := make([]report.Place, len(store.Favorites))
+		for i, fav := range store.Favorites {
+			reports[i] = report.NewPlace(fav)
+		}
+		return writeJSON(stdout, stderr, reports)
+	case "text":
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, fav := range store.Favorites {
+			fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%s\t%s\n", fav.Name, fav.Latitude, fav.Longitude, fav.Profile, fav.Address)
+		}
+		tw.Flush()
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+	return 2
+}
+
+func runFavoritesShow(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites show", "[--format text|json] <name>", stderr)
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		fs.Usage()
+		return 2
+	}
+
+	fav, ok := store.FindFavorite(name)
+	if !ok {
+		fmt.Fprintf(stderr, "error: no favorite named '%s'\n", name)
+		return 1
+	}
+
+	switch *format {
+	case "json":
+		return writeJSON(stdout, stderr, report.NewPlace(fav))
+	case "text":
+		fmt.Fprintf(stdout, "Name:      %s\n", fav.Name)
+		fmt.Fprintf(stdout, "Address:   %s\n", fav.Address)
+		fmt.Fprintf(stdout, "Latitude:  %.6f\n", fav.Latitude)
+		fmt.Fprintf(stdout, "Longitude: %.6f\n", fav.Longitude)
+		if fav.Profile != "" {
+			fmt.Fprintf(stdout, "Profile:   %s\n", fav.Profile)
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+	return 2
+}
+
+func runFavoritesAdd(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites add", "[--name name] [--profile name] (--lat lat --lon lon | <search query>)", stderr)
+	name := fs.String("name", "", "name of the favorite, defaults to the search result name")
+	address := fs.String("address", "", "address shown below the name")
+	profile := fs.String("profile", "", "scoring profile used for this favorite: "+strings.Join(forecast.ProfileNames(), ", "))
+	var coords placeFlags
+	coords.registerCoordinates(fs)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	hasCoords, err := coords.hasCoordinates()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	if *profile != "" {
+		resolved, err := forecast.ProfileByName(*profile)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 2
+		}
+		*profile = resolved.Name
+	}
+
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+
+	var place domain.Place
+	switch {
+	case hasCoords:
+		if *name == "" {
+			*name = query
+		}
+		if *name == "" {
+			fmt.Fprintln(stderr, "error: a name is required when adding coordinates")
+			return 2
+		}
+		place = domain.Place{Latitude: coords.lat, Longitude: coords.lon}
+	case query != "":
+		places, err := photon.SearchPlaces(query)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		place = places[0]
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	if *name != "" {
+		place.Name = *name
+	}
+	if *address != "" {
+		place.Address = *address
+	}
+	place.Profile = *profile
+
+	if existing, ok := store.FindFavorite(place.Name); ok {
+		if existing.Latitude == place.Latitude && existing.Longitude == place.Longitude {
+			return 0
+		}
+		fmt.Fprintf(stderr, "error: a favorite named '%s' already exists\n", existing.Name)
+		return 1
+	}
+
+	if err := store.AddFavorite(place); err != nil {
+		fmt.Fprintf(stderr, "error: failed to save favorites: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Added %s (%.4f, %.4f)\n", place.Name, place.Latitude, place.Longitude)
+	return 0
+}
+
+func runFavoritesRemove(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites remove", "<name>", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	name := strings.Join(fs.Args(), " ")
+	if name == "" {
+		fs.Usage()
+		return 2
+	}
+
+	fav, ok := store.FindFavorite(name)
+	if !ok {
+		fmt.Fprintf(stderr, "error: no favorite named '%s'\n", name)
+		return 1
+	}
+
+	if err := store.RemoveFavorite(fav); err != nil {
+		fmt.Fprintf(stderr, "error: failed to save favorites: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Removed %s\n", fav.Name)
+	return 0
+}
+
+func runFavoritesRename(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites rename", "<old name> <new name>", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if fs.NArg() != 2 || strings.TrimSpace(fs.Arg(1)) == "" {
+		fs.Usage()
+		return 2
+	}
+
+	oldName, newName := fs.Arg(0), strings.TrimSpace(fs.Arg(1))
+	if err := store.RenameFavorite(oldName, newName); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Renamed %s to %s\n", oldName, newName)
+	return 0
+}
+
+func runFavoritesProfile(store *storage.FavoritesStore, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("favorites profile", "<name> <profile|none>", stderr)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	name, profileName := fs.Arg(0), fs.Arg(1)
+	if profileName == "none" {
+		profileName = ""
+	} else {
+		profile, err := forecast.ProfileByName(profileName)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 2
+		}
+		profileName = profile.Name
+	}
+
+	if err := store.SetFavoriteProfile(name, profileName); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	if profileName == "" {
+		fmt.Fprintf(stdout, "%s now uses the configured profile\n", name)
+		return 0
+	}
+	fmt.Fprintf(stdout, "%s now uses the %s profile\n", name, profileName)
+	return 0
+}
@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+	"driffaud.fr/odin/internal/util"
+)
+
+func runForecast(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("forecast", "[flags] [favorite name or search query]", stderr)
+
+	var places placeFlags
+	places.register(fs)
+	format := fs.String("format", "text", "output format: text or json")
+	hours := fs.Int("hours", cfg.Forecast.TableHours, "number of hourly rows to print")
+	clearProbability := fs.Bool("clear-probability", cfg.Forecast.ShowClearProbability, "add the ensemble clear-sky probability column to the hourly table")
+	cloudLayers := fs.Bool("cloud-layers", cfg.Forecast.ShowCloudLayers, "add the low/mid/high cloud cover column to the hourly table")
+	profileName := profileFlag(fs)
+	date := dateFlag(fs)
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+		return 2
+	}
+	if *hours < 0 {
+		fmt.Fprintln(stderr, "error: --hours must not be negative")
+		return 2
+	}
+
+	store, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return 1
+	}
+
+	place, err := places.resolve(cfg, store, fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	profile, err := cfg.ResolveProfile(*profileName, place)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	weather, err := placeClient(store, place).GetWeather(place.Latitude, place.Longitude)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	nightDate, err := util.ParseNightDate(*date, weather.Location(), time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	at := astro.ObserverTime(nightDate, weather.Location(), time.Now())
+	forecastReport := report.BuildForecast(place, weather, *hours, profile, at)
+
+	if *format == "json" {
+		return writeJSON(stdout, stderr, forecastReport)
+	}
+
+	writeForecastText(stdout, forecastReport, weather.Location(), cfg.Units, *clearProbability, *cloudLayers)
+	return 0
+}
+
+func writeForecastText(w io.Writer, forecastReport report.Forecast, loc *time.Location, units util.Units, showClearProbability, showCloudLayers bool) {
+	title := forecastReport.Place.Name
+	if forecastReport.Place.Address != "" {
+		title += " (" + forecastReport.Place.Address + ")"
+	}
+	fmt.Fprintf(w, "%s [%.4f, %.4f]\n", title, forecastReport.Place.Latitude, forecastReport.Place.Longitude)
+	fmt.Fprintln(w, i18n.T("weather.profile", map[string]any{"Profile": forecastReport.Profile}))
+	if date, err := time.Parse(time.DateOnly, forecastReport.Date); err == nil {
+		fmt.Fprintln(w, i18n.T("weather.night_of", map[string]any{
+			"Weekday": i18n.Weekday(date.Weekday()),
+			"Date":    forecastReport.Date,
+		}))
+	}
+	fmt.Fprintln(w)
+
+	sun, moon := forecastReport.Sun, forecastReport.Moon
+	fmt.Fprintln(w, format.Sun(timeOrZero(sun.Sunset), timeOrZero(sun.Dusk), timeOrZero(sun.Dawn), timeOrZero(sun.Sunrise), loc))
+	if darkness := format.Darkness(sun.Darkness, sun.DarkestTime, sun.DarkestAltitude, sun.PolarNight, loc); darkness != "" {
+		fmt.Fprintln(w, darkness)
+	}
+	fmt.Fprintln(w, format.Moon(moon.Emoji, moon.Phase, moon.Illumination, timeOrZero(moon.Moonrise), timeOrZero(moon.Moonset), loc))
+	fmt.Fprintln(w)
+
+	if forecastReport.Forecasted {
+		writeNightText(w, forecastReport, loc, units)
+	} else {
+		fmt.Fprintln(w, i18n.T("weather.beyond_horizon", nil))
+	}
+
+	if len(forecastReport.Outlook) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, i18n.T("outlook.title", nil))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			i18n.T("outlook.night", nil),
+			i18n.T("outlook.rating", nil),
+			i18n.T("outlook.window", nil),
+			i18n.T("outlook.clouds", nil),
+			i18n.T("outlook.moon", nil),
+		)
+		for _, outlook := range forecastReport.Outlook {
+			date, _ := time.Parse(time.DateOnly, outlook.Date)
+			window := i18n.T("outlook.no_window", nil)
+			if bp := outlook.Night.BestPeriod; bp != nil {
+				window = format.Period(bp.Start, bp.End, loc)
+			}
+			fmt.Fprintf(tw, "%s %d\t%d/5\t%s\t%d%%\t%s %.0f%%\n",
+				i18n.Weekday(date.Weekday()),
+				date.Day(),
+				outlook.Night.Rating,
+				window,
+				outlook.Night.CloudCover,
+				outlook.Moon.Emoji,
+				outlook.Moon.Illumination,
+			)
+		}
+		tw.Flush()
+	}
+
+	if len(forecastReport.Planets) > 0 {
+		fmt.Fprintln(w)
+		writePlanetsText(w, forecastReport.Planets, loc)
+	}
+
+	fmt.Fprintln(w)
+	writeTargetsText(w, forecastReport.Targets, forecastReport.Forecasted, loc)
+
+	if len(forecastReport.Hours) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, i18n.T("forecast.title", nil))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{i18n.T("forecast.hour", nil), i18n.T("forecast.clouds", nil)}
+	if showCloudLayers {
+		header = append(header, i18n.T("forecast.cloud_layers", nil))
+	}
+	if showClearProbability {
+		header = append(header, i18n.T("forecast.clear_probability", nil))
+	}
+	header = append(header,
+		i18n.T("forecast.rain", nil),
+		i18n.T("forecast.seeing", nil),
+		i18n.T("forecast.transparency", nil),
+		i18n.T("forecast.moon", nil),
+		i18n.T("forecast.wind", nil),
+		i18n.T("forecast.gusts", nil),
+		i18n.T("forecast.humidity", nil),
+		i18n.T("forecast.fog", nil),
+		i18n.T("forecast.temp", nil),
+		i18n.T("forecast.dew", nil),
+		i18n.T("forecast.dew_risk", nil),
+	)
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for _, hour := range forecastReport.Hours {
+		row := []string{hour.DateTime.Format("15h"), fmt.Sprintf("%d%%", hour.Clouds)}
+		if showCloudLayers {
+			row = append(row, format.CloudLayers(hour.CloudsLow, hour.CloudsMid, hour.CloudsHigh))
+		}
+		if showClearProbability {
+			row = append(row, format.KnownPercent(hour.ClearProbability))
+		}
+		row = append(row,
+			fmt.Sprintf("%d%%", hour.PrecipitationProbability),
+			format.Seeing(hour.Seeing, hour.SeeingArcsec),
+			format.TransparencyIndex(hour.Transparency),
+			format.MoonPosition(astro.MoonPosition{Altitude: hour.MoonAltitude, PhaseEmoji: hour.MoonEmoji}),
+			units.WindSpeed(hour.WindSpeed, 1),
+			fmt.Sprintf("%s %s", units.WindSpeed(hour.WindGusts, 0), i18n.T("shake_risk."+hour.ShakeRisk, nil)),
+			fmt.Sprintf("%d%%", hour.Humidity),
+			fmt.Sprintf("%d%%", hour.FogProbability),
+			units.Temperature(hour.Temperature, 1),
+			units.Temperature(hour.DewPoint, 1),
+			fmt.Sprintf("%s %d%%", i18n.T("dew_risk."+hour.DewRisk, nil), hour.DewHeaterPower),
+		)
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+	tw.Flush()
+}
+
+// writeNightText prints the dark windows, observation windows and conditions of the night
+func writeNightText(w io.Writer, forecastReport report.Forecast, loc *time.Location, units util.Units) {
+	night := forecastReport.Night
+	fmt.Fprintln(w, i18n.T("weather.conditions_title", nil))
+	fmt.Fprintln(w, format.DarkWindows(astroIntervals(night.DarkWindows), forecastReport.Sun.Darkness, loc))
+	if len(night.Windows) > 0 {
+		fmt.Fprintln(w, i18n.T("weather.best_windows", nil))
+		for _, window := range night.Windows {
+			confidence := -1
+			if window.Confidence != nil {
+				confidence = *window.Confidence
+			}
+			fmt.Fprintln(w, "  "+format.Window(window.Start, window.End, window.Hours, float64(window.CloudCover), window.Seeing, window.Rating, confidence, loc))
+		}
+	} else {
+		fmt.Fprintln(w, i18n.T("weather.unfavorable", map[string]any{
+			"CloudCover": night.CloudCover,
+		}))
+	}
+	fmt.Fprintln(w, format.Conditions(units, float64(night.Temperature), night.Humidity, float64(night.WindSpeed), night.WindDirectionText, float64(night.DewPoint)))
+	fmt.Fprintln(w, format.PrecipitationAndSeeing(night.PrecipitationProbability, night.Seeing))
+	fmt.Fprintln(w, format.Transparency(night.Transparency))
+	fmt.Fprintln(w, format.Dew(night.DewRisk, timeOrZero(night.DewStart), night.DewHeaterPower, loc))
+	fmt.Fprintln(w, format.Gusts(units, float64(night.MaxWindGusts), night.ShakeRisk))
+	fmt.Fprintln(w, format.Fog(timeOrZero(night.FogStart), night.MaxFogProbability, loc))
+}
+
+// writePlanetsText prints when the planets rise, transit and set, how they look and when
+// they can be observed within the observation windows
+func writePlanetsText(w io.Writer, planets []report.Planet, loc *time.Location) {
+	fmt.Fprintln(w, i18n.T("planets.title", nil))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		i18n.T("planets.planet", nil),
+		i18n.T("planets.rise", nil),
+		i18n.T("planets.transit", nil),
+		i18n.T("planets.set", nil),
+		i18n.T("planets.max_altitude", nil),
+		i18n.T("planets.magnitude", nil),
+		i18n.T("planets.diameter", nil),
+		i18n.T("planets.elongation", nil),
+		i18n.T("planets.clear", nil),
+	)
+	for _, planet := range planets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.0f°\t%.1f\t%.0f\"\t%s\t%s\n",
+			i18n.T("planets."+planet.Name, nil),
+			format.Time(timeOrZero(planet.Rise), loc),
+			format.Time(timeOrZero(planet.Transit), loc),
+			format.Time(timeOrZero(planet.Set), loc),
+			planet.MaxAltitude,
+			planet.Magnitude,
+			planet.Diameter,
+			format.Elongation(planet.Elongation),
+			format.PlanetClear(len(planet.Observable) > 0, astroIntervals(planet.Clear), planet.Clear != nil, loc),
+		)
+	}
+	tw.Flush()
+}
+
+// writeTargetsText prints the deep-sky objects best placed during the night, noting when
+// they are ranked over the dark windows only because the night is not forecast yet
+func writeTargetsText(w io.Writer, targets []report.Target, forecasted bool, loc *time.Location) {
+	fmt.Fprintln(w, i18n.T("targets.title", nil))
+	if !forecasted {
+		fmt.Fprintln(w, i18n.T("targets.dark_only", nil))
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(w, i18n.T("targets.none", nil))
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		i18n.T("targets.object", nil),
+		i18n.T("targets.name", nil),
+		i18n.T("targets.type", nil),
+		i18n.T("targets.magnitude", nil),
+		i18n.T("targets.up", nil),
+		i18n.T("targets.periods", nil),
+		i18n.T("targets.transit", nil),
+		i18n.T("targets.moon", nil),
+	)
+	for _, target := range targets {
+		magnitude := format.Unknown
+		if target.Magnitude != nil {
+			magnitude = fmt.Sprintf("%.1f", *target.Magnitude)
+		}
+		moon := format.Unknown
+		if target.MoonUp {
+			moon = fmt.Sprintf("%.0f°", target.MoonSeparation)
+		}
+
+		name := target.Name
+		if name == "" {
+			name = target.Designation
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f h\t%s\t%s %.0f°\t%s\n",
+			target.ID,
+			name,
+			i18n.T("targets."+target.Type, nil),
+			magnitude,
+			target.Hours,
+			format.Intervals(astroIntervals(target.Up), loc),
+			format.Time(target.Transit, loc),
+			target.TransitAltitude,
+			moon,
+		)
+	}
+	tw.Flush()
+}
+
+// timeOrZero returns the time t points to, or the zero time when it is unknown
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// astroIntervals converts the intervals of a report back for formatting
+func astroIntervals(intervals []report.Interval) []astro.Interval {
+	converted := make([]astro.Interval, len(intervals))
+	for i, interval := range intervals {
+		converted[i] = astro.Interval{Start: interval.Start, End: interval.End}
+	}
+	return converted
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+)
+
+func runModels(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("models", "[flags] [favorite name or search query]", stderr)
+
+	var places placeFlags
+	places.register(fs)
+	format := fs.String("format", "text", "output format: text or json")
+	hours := fs.Int("hours", cfg.Forecast.TableHours, "number of hourly rows to print")
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+		return 2
+	}
+	if *hours < 0 {
+		fmt.Fprintln(stderr, "error: --hours must not be negative")
+		return 2
+	}
+
+	store, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return 1
+	}
+
+	place, err := places.resolve(cfg, store, fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	comparison, err := placeClient(store, place).GetModelComparison(place.Latitude, place.Longitude)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	comparisonReport := report.BuildModelComparison(place, comparison, *hours)
+
+	if *format == "json" {
+		return writeJSON(stdout, stderr, comparisonReport)
+	}
+
+	writeModelsText(stdout, comparisonReport)
+	return 0
+}
+
+func writeModelsText(w io.Writer, comparison report.ModelComparison) {
+	fmt.Fprintf(w, "%s [%.4f, %.4f]\n", comparison.Place.Name, comparison.Place.Latitude, comparison.Place.Longitude)
+	if comparison.NightAgreement != nil {
+		fmt.Fprintln(w, i18n.T("models.night_agreement", map[string]any{"Agreement": *comparison.NightAgreement}))
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{i18n.T("forecast.hour", nil)}
+	header = append(header, comparison.Models...)
+	header = append(header, i18n.T("models.mean", nil), i18n.T("models.agreement", nil))
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for _, hour := range comparison.Hours {
+		row := []string{hour.DateTime.Format("15h")}
+		for _, clouds := range hour.CloudCover {
+			row = append(row, format.KnownPercent(clouds))
+		}
+		row = append(row, fmt.Sprintf("%d%%", hour.Mean), format.KnownPercent(hour.Agreement))
+		fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+	tw.Flush()
+}
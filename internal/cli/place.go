@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/api/photon"
+	"driffaud.fr/odin/internal/platform/storage"
+)
+
+// placeFlags holds the flags used to select a place from the command line
+type placeFlags struct {
+	favorite string
+	lat      float64
+	lon      float64
+	fs       *flag.FlagSet
+}
+
+// register adds the place selection flags to a flag set
+func (p *placeFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&p.favorite, "favorite", "", "name of a favorite place")
+	p.registerCoordinates(fs)
+}
+
+// registerCoordinates adds only the --lat and --lon flags to a flag set
+func (p *placeFlags) registerCoordinates(fs *flag.FlagSet) {
+	p.fs = fs
+	fs.Float64Var(&p.lat, "lat", 0, "latitude in decimal degrees")
+	fs.Float64Var(&p.lon, "lon", 0, "longitude in decimal degrees")
+}
+
+// hasCoordinates reports whether both --lat and --lon were given
+func (p *placeFlags) hasCoordinates() (bool, error) {
+	latSet, lonSet := false, false
+	p.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "lat":
+			latSet = true
+		case "lon":
+			lonSet = true
+		}
+	})
+
+	if latSet != lonSet {
+		return false, errors.New("--lat and --lon must be used together")
+	}
+	if !latSet {
+		return false, nil
+	}
+	if p.lat < -90 || p.lat > 90 {
+		return false, fmt.Errorf("latitude %g out of range [-90, 90]", p.lat)
+	}
+	if p.lon < -180 || p.lon > 180 {
+		return false, fmt.Errorf("longitude %g out of range [-180, 180]", p.lon)
+	}
+	return true, nil
+}
+
+// resolve determines the place from the flags and the remaining positional arguments.
+// A positional query first matches a favorite by name and otherwise goes through Photon.
+// Without any of them, the configured default place is used.
+func (p *placeFlags) resolve(cfg config.Config, store *storage.FavoritesStore, args []string) (domain.Place, error) {
+	query := strings.TrimSpace(strings.Join(args, " "))
+
+	hasCoords, err := p.hasCoordinates()
+	if err != nil {
+		return domain.Place{}, err
+	}
+
+	switch {
+	case hasCoords:
+		name := query
+		if name == "" {
+			name = fmt.Sprintf("%.4f, %.4f", p.lat, p.lon)
+		}
+		return domain.Place{Name: name, Latitude: p.lat, Longitude: p.lon}, nil
+	case p.favorite != "":
+		if place, ok := store.FindFavorite(p.favorite); ok {
+			return place, nil
+		}
+		return domain.Place{}, fmt.Errorf("no favorite named '%s'", p.favorite)
+	case query != "":
+		if place, ok := store.FindFavorite(query); ok {
+			return place, nil
+		}
+		places, err := photon.SearchPlaces(query)
+		if err != nil {
+			return domain.Place{}, err
+		}
+		return places[0], nil
+	case cfg.HasDefaultPlace():
+		return cfg.ResolveDefaultPlace(store)
+	}
+
+	return domain.Place{}, errors.New("a place is required: give a favorite name, a search query or --lat/--lon")
+}
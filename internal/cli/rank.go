@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/format"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/ranking"
+	"driffaud.fr/odin/internal/report"
+	"driffaud.fr/odin/internal/util"
+)
+
+// rankReport is the JSON representation of a ranked favorite
+type rankReport struct {
+	Rank            int           `json:"rank"`
+	Place           report.Place  `json:"place"`
+	Profile         string        `json:"profile,omitempty"`
+	BestWindowHours int           `json:"best_window_hours"`
+	Night           *report.Night `json:"night,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+func runRank(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("rank", "[--format text|json] [--profile name] [--date day]", stderr)
+	format := fs.String("format", "text", "output format: text or json")
+	profileName := profileFlag(fs)
+	date := dateFlag(fs)
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+		return 2
+	}
+	// Every favorite is ranked with the same profile, see ranking.RankPlaces
+	profile, err := cfg.ResolveProfile(*profileName, domain.Place{})
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	store, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return 1
+	}
+	if len(store.Favorites) == 0 {
+		fmt.Fprintln(stderr, "error: no favorites to rank, add some with 'odin favorites add'")
+		return 1
+	}
+
+	entries := ranking.RankPlaces(favoritesClient(store), store.Favorites, *date, profile)
+
+	failed, past := 0, 0
+	for _, entry := range entries {
+		if entry.Err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", entry.Place.Name, entry.Err)
+			failed++
+		}
+		if errors.Is(entry.Err, util.ErrPastDate) {
+			past++
+		}
+	}
+	code := 0
+	switch {
+	case past == len(entries):
+		// The night is past at every favorite: a usage error
+		code = 2
+	case failed == len(entries):
+		code = 1
+	}
+
+	if *format == "json" {
+		reports := make([]rankReport, len(entries))
+		for i, entry := range entries {
+			reports[i] = newRankReport(i+1, entry)
+		}
+		if jsonCode := writeJSON(stdout, stderr, reports); jsonCode != 0 {
+			return jsonCode
+		}
+		return code
+	}
+
+	writeRankText(stdout, entries, profile.Name)
+	return code
+}
+
+// writeRankText prints the ranked places with the best window and conditions of their night,
+// all analyzed with the named profile
+func writeRankText(w io.Writer, entries []ranking.Entry, profile string) {
+	fmt.Fprintln(w, i18n.T("ranking.profile", map[string]any{"Profile": profile}))
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "#\t%s\t%s\t%s\t%s\t%s\n",
+		i18n.T("ranking.place", nil),
+		i18n.T("ranking.best_window", nil),
+		i18n.T("forecast.clouds", nil),
+		i18n.T("forecast.seeing", nil),
+		i18n.T("forecast.rain", nil),
+	)
+	for i, entry := range entries {
+		if entry.Err != nil {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t-\t-\t-\n", i+1, entry.Place.Name, i18n.T("ranking.unavailable", nil))
+			continue
+		}
+
+		window := "-"
+		if best, ok := entry.Night.BestWindow(); ok {
+			window = fmt.Sprintf("%s (%dh)", format.Period(best.Start, best.End, best.Start.Location()), best.Hours())
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d%%\t%d/5\t%d%%\n",
+			i+1,
+			entry.Place.Name,
+			window,
+			entry.Night.DisplayCloudCover,
+			entry.Night.SeeingIndex,
+			entry.Night.MaxPrecipProbability,
+		)
+	}
+	tw.Flush()
+}
+
+func newRankReport(position int, entry ranking.Entry) rankReport {
+	rank := rankReport{
+		Rank:            position,
+		Place:           report.NewPlace(entry.Place),
+		Profile:         entry.Profile.Name,
+		BestWindowHours: entry.BestWindowHours(),
+	}
+	if entry.Err != nil {
+		rank.Error = entry.Err.Error()
+		return rank
+	}
+
+	night := report.NewNight(entry.Night)
+	rank.Night = &night
+	return rank
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/server"
+)
+
+func runServe(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("serve", "[--addr host:port] [--profile name]", stderr)
+	addr := fs.String("addr", cfg.Server.Address, "address to listen on")
+	profileName := fs.String("profile", cfg.Profile, "default scoring profile: "+strings.Join(forecast.ProfileNames(), ", "))
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if _, err := forecast.ProfileByName(*profileName); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	cfg.Profile = *profileName
+
+	store, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Listening on http://%s\n", listener.Addr())
+	if err := server.New(cfg, openmeteo.DefaultClient, store).Serve(ctx, listener); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
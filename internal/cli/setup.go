@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/api/airquality"
+	"driffaud.fr/odin/internal/platform/api/archive"
+	"driffaud.fr/odin/internal/platform/api/ensemble"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/platform/api/photon"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/util"
+	"driffaud.fr/odin/internal/verification"
+)
+
+// GlobalOptions are the flags accepted before the command name
+type GlobalOptions struct {
+	ConfigPath string
+	Language   string
+	Units      string
+}
+
+func globalFlagSet(opts *GlobalOptions, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("odin", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.ConfigPath, "config", "", "path of the configuration file (default: config.toml next to favorites.json)")
+	fs.StringVar(&opts.Language, "lang", "", "interface language: en or fr (overrides the configuration)")
+	fs.StringVar(&opts.Units, "units", "", "display units: metric or imperial (overrides the configuration)")
+	return fs
+}
+
+// Setup parses the global flags, loads the configuration, initializes the
+// translations and configures the API clients. It returns the configuration,
+// the remaining arguments and, when the program must stop, its exit code: 2 for
+// every setup failure, which odin check reports as an error rather than a no-go.
+func Setup(args []string, stderr io.Writer) (config.Config, []string, int, bool) {
+	var opts GlobalOptions
+	fs := globalFlagSet(&opts, stderr)
+	fs.Usage = func() { printUsage(stderr) }
+	if code, ok := parseFlags(fs, args); !ok {
+		return config.Config{}, nil, code, false
+	}
+
+	path := opts.ConfigPath
+	if path == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return config.Config{}, nil, 2, false
+		}
+		path = defaultPath
+	}
+
+	cfg, err := config.Load(path, opts.ConfigPath != "")
+	if err != nil {
+		fmt.Fprintf(stderr, "error: invalid configuration: %v\n", err)
+		return config.Config{}, nil, 2, false
+	}
+
+	if opts.Language != "" {
+		cfg.Language = opts.Language
+	}
+	if opts.Units != "" {
+		cfg.Units = util.Units(opts.Units)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return config.Config{}, nil, 2, false
+	}
+
+	if err := i18n.Init(cfg.Language); err != nil {
+		fmt.Fprintf(stderr, "error initializing i18n: %v\n", err)
+		return config.Config{}, nil, 2, false
+	}
+
+	configureClients(cfg)
+
+	return cfg, fs.Args(), 0, true
+}
+
+// favoritesClient returns a copy of the default forecast client that keeps the forecasts
+// fetched for the favorites of store in the history, for odin verify and the weather view
+// to check them later. It falls back to the default client when the history cannot be loaded.
+func favoritesClient(store *storage.FavoritesStore) *openmeteo.Client {
+	recorder, err := verification.NewRecorder(store.Favorites)
+	if err != nil {
+		return openmeteo.DefaultClient
+	}
+	return openmeteo.DefaultClient.WithRecorder(recorder)
+}
+
+// placeClient returns the forecast client for place: one that records its forecast when it
+// is a favorite, the default client for a searched place or coordinates
+func placeClient(store *storage.FavoritesStore, place domain.Place) *openmeteo.Client {
+	if !store.IsFavorite(place) {
+		return openmeteo.DefaultClient
+	}
+	return favoritesClient(store)
+}
+
+// configureClients points the default API clients at the configured services
+func configureClients(cfg config.Config) {
+	openmeteo.DefaultClient.BaseURL = cfg.API.OpenMeteoURL
+	openmeteo.DefaultClient.ForecastDays = cfg.API.ForecastDays
+	openmeteo.DefaultClient.Models = cfg.API.Models
+	openmeteo.DefaultClient.ComparisonModels = cfg.API.ComparisonModelList()
+
+	airquality.DefaultClient.BaseURL = cfg.API.AirQualityURL
+	airquality.DefaultClient.ForecastDays = min(cfg.API.ForecastDays, airquality.MaxForecastDays)
+
+	ensemble.DefaultClient.BaseURL = cfg.API.EnsembleURL
+	ensemble.DefaultClient.Model = cfg.API.EnsembleModel
+	ensemble.DefaultClient.ForecastDays = cfg.API.ForecastDays
+
+	archive.DefaultClient.BaseURL = cfg.API.ArchiveURL
+
+	photon.DefaultClient.BaseURL = cfg.API.PhotonURL
+	photon.DefaultClient.Language = cfg.API.PhotonLanguage
+	if photon.DefaultClient.Language == "" {
+		photon.DefaultClient.Language = i18n.Language()
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+	"driffaud.fr/odin/internal/util"
+	"driffaud.fr/odin/internal/verification"
+)
+
+func runVerify(cfg config.Config, args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("verify", "[--format text|json] [favorite name]", stderr)
+	format := fs.String("format", "text", "output format: text or json")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q (expected text or json)\n", *format)
+		return 2
+	}
+
+	favorites, err := storage.NewFavoritesStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load favorites: %v\n", err)
+		return 1
+	}
+	history, err := storage.NewHistoryStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: failed to load the forecast history: %v\n", err)
+		return 1
+	}
+
+	places := favorites.Favorites
+	if name := strings.Join(fs.Args(), " "); name != "" {
+		fav, ok := favorites.FindFavorite(name)
+		if !ok {
+			fmt.Fprintf(stderr, "error: no favorite named '%s'\n", name)
+			return 1
+		}
+		places = []domain.Place{fav}
+	}
+	if len(places) == 0 {
+		fmt.Fprintln(stderr, "error: no favorites to verify, add some with 'odin favorites add'")
+		return 1
+	}
+
+	code := 0
+	var reports []report.Verification
+	for _, place := range places {
+		profile, err := cfg.ResolveProfile("", place)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", place.Name, err)
+			code = 1
+			continue
+		}
+
+		results, err := verification.VerifyPlace(place, history.PlaceRecords(place.Latitude, place.Longitude), profile.Thresholds.CloudCover, time.Now())
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", place.Name, err)
+			code = 1
+			continue
+		}
+		reports = append(reports, report.NewVerification(place, results))
+	}
+
+	if *format == "json" {
+		if jsonCode := writeJSON(stdout, stderr, reports); jsonCode != 0 {
+			return jsonCode
+		}
+		return code
+	}
+
+	for i, verificationReport := range reports {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		writeVerificationText(stdout, verificationReport, cfg.Units)
+	}
+	return code
+}
+
+func writeVerificationText(w io.Writer, verificationReport report.Verification, units util.Units) {
+	fmt.Fprintln(w, verificationReport.Place.Name)
+	if len(verificationReport.Results) == 0 {
+		fmt.Fprintln(w, "  "+i18n.T("verify.no_data", nil))
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+		i18n.T("verify.model", nil),
+		i18n.T("verify.lead", nil),
+		i18n.T("verify.hours", nil),
+		i18n.T("verify.cloud_mae", nil),
+		i18n.T("verify.cloud_bias", nil),
+		i18n.T("verify.clear_hit_rate", nil),
+		i18n.T("verify.temperature_mae", nil),
+		i18n.T("verify.temperature_bias", nil),
+	)
+	for _, result := range verificationReport.Results {
+		temperatureMAE, temperatureBias := "-", "-"
+		if result.TemperatureHours > 0 {
+			temperatureMAE = strings.TrimPrefix(units.TemperatureDifference(result.TemperatureMAE, 1), "+")
+			temperatureBias = units.TemperatureDifference(result.TemperatureBias, 1)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.0f%%\t%+.0f%%\t%.0f%%\t%s\t%s\t\n",
+			result.Model,
+			i18n.T("verify.lead_day", map[string]any{"Day": result.LeadDay}),
+			result.Hours,
+			result.CloudMAE,
+			result.CloudBias,
+			result.ClearHitRate,
+			temperatureMAE,
+			temperatureBias,
+		)
+	}
+	tw.Flush()
+}
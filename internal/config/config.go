@@ -0,0 +1,295 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/util"
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the name of the configuration file inside the application config directory
+const FileName = "config.toml"
+
+// Config holds the user configuration
+type Config struct {
+	Language     string         `toml:"language"`
+	Profile      string         `toml:"profile"`
+	Units        util.Units     `toml:"units"`
+	DefaultPlace PlaceConfig    `toml:"default_place"`
+	API          APIConfig      `toml:"api"`
+	Forecast     ForecastConfig `toml:"forecast"`
+	Server       ServerConfig   `toml:"server"`
+}
+
+// PlaceConfig selects the place used when none is given, either a favorite or coordinates
+type PlaceConfig struct {
+	Favorite  string   `toml:"favorite"`
+	Name      string   `toml:"name"`
+	Latitude  *float64 `toml:"latitude"`
+	Longitude *float64 `toml:"longitude"`
+}
+
+// APIConfig configures the external services
+type APIConfig struct {
+	PhotonURL        string `toml:"photon_url"`
+	PhotonLanguage   string `toml:"photon_language"`
+	OpenMeteoURL     string `toml:"openmeteo_url"`
+	AirQualityURL    string `toml:"airquality_url"`
+	EnsembleURL      string `toml:"ensemble_url"`
+	ArchiveURL       string `toml:"archive_url"`
+	EnsembleModel    string `toml:"ensemble_model"`
+	ForecastDays     int    `toml:"forecast_days"`
+	Models           string `toml:"models"`
+	ComparisonModels string `toml:"comparison_models"` // comma separated
+}
+
+// ForecastConfig configures the forecast analysis and display.
+// Unset thresholds come from the scoring profile.
+type ForecastConfig struct {
+	CloudCoverThreshold  *int     `toml:"cloud_cover_threshold"`
+	MinWindowHours       *int     `toml:"min_window_hours"`
+	MaxMoonAltitude      *float64 `toml:"max_moon_altitude"`
+	MinTargetAltitude    *float64 `toml:"min_target_altitude"`
+	TableHours           int      `toml:"table_hours"`
+	ShowClearProbability bool     `toml:"show_clear_probability"`
+	ShowCloudLayers      bool     `toml:"show_cloud_layers"`
+	WindTolerance        *string  `toml:"wind_tolerance"` // "open", "sheltered" or "dome"
+}
+
+// ServerConfig configures the HTTP API server
+type ServerConfig struct {
+	Address string `toml:"address"`
+}
+
+// Default returns the configuration used when no file exists
+func Default() Config {
+	return Config{
+		Profile: forecast.DefaultProfileName,
+		Units:   util.Metric,
+		API: APIConfig{
+			PhotonURL:        "https://photon.komoot.io/api",
+			OpenMeteoURL:     "https://api.open-meteo.com/v1/forecast",
+			AirQualityURL:    "https://air-quality-api.open-meteo.com/v1/air-quality",
+			EnsembleURL:      "https://ensemble-api.open-meteo.com/v1/ensemble",
+			ArchiveURL:       "https://archive-api.open-meteo.com/v1/archive",
+			EnsembleModel:    "icon_seamless",
+			ForecastDays:     7,
+			Models:           "best_match",
+			ComparisonModels: strings.Join(openmeteo.DefaultComparisonModels, ","),
+		},
+		Forecast: ForecastConfig{
+			TableHours: 24,
+		},
+		Server: ServerConfig{
+			Address: "127.0.0.1:8080",
+		},
+	}
+}
+
+// DefaultPath returns the path of the configuration file next to the favorites
+func DefaultPath() (string, error) {
+	dir, err := storage.AppConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the configuration file at path on top of the defaults.
+// A missing file is not an error unless required is set.
+func Load(path string, required bool) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	meta, err := toml.Decode(string(data), &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return cfg, fmt.Errorf("%s: unknown key(s): %s", path, strings.Join(keys, ", "))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("%s:\n  %s", path, strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that every value is usable
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Language != "" && c.Language != "en" && c.Language != "fr" {
+		errs = append(errs, fmt.Errorf("language must be \"en\" or \"fr\", got %q", c.Language))
+	}
+	if _, err := forecast.ProfileByName(c.Profile); err != nil {
+		errs = append(errs, fmt.Errorf("profile: %w", err))
+	}
+	if c.Units != util.Metric && c.Units != util.Imperial {
+		errs = append(errs, fmt.Errorf("units must be %q or %q, got %q", util.Metric, util.Imperial, c.Units))
+	}
+
+	place := c.DefaultPlace
+	if (place.Latitude == nil) != (place.Longitude == nil) {
+		errs = append(errs, errors.New("default_place.latitude and default_place.longitude must be set together"))
+	}
+	if place.Favorite != "" && place.Latitude != nil {
+		errs = append(errs, errors.New("default_place.favorite cannot be combined with coordinates"))
+	}
+	if place.Latitude != nil && (*place.Latitude < -90 || *place.Latitude > 90) {
+		errs = append(errs, fmt.Errorf("default_place.latitude must be between -90 and 90, got %g", *place.Latitude))
+	}
+	if place.Longitude != nil && (*place.Longitude < -180 || *place.Longitude > 180) {
+		errs = append(errs, fmt.Errorf("default_place.longitude must be between -180 and 180, got %g", *place.Longitude))
+	}
+
+	urls := []struct{ key, value string }{
+		{"api.photon_url", c.API.PhotonURL},
+		{"api.openmeteo_url", c.API.OpenMeteoURL},
+		{"api.airquality_url", c.API.AirQualityURL},
+		{"api.ensemble_url", c.API.EnsembleURL},
+		{"api.archive_url", c.API.ArchiveURL},
+	}
+	for _, u := range urls {
+		if parsed, err := url.Parse(u.value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("%s must be an http(s) URL, got %q", u.key, u.value))
+		}
+	}
+	if c.API.ForecastDays < 1 || c.API.ForecastDays > 16 {
+		errs = append(errs, fmt.Errorf("api.forecast_days must be between 1 and 16, got %d", c.API.ForecastDays))
+	}
+	if c.API.Models == "" {
+		errs = append(errs, errors.New("api.models must not be empty"))
+	}
+	if len(c.API.ComparisonModelList()) == 0 {
+		errs = append(errs, errors.New("api.comparison_models must list at least one model"))
+	}
+	if c.API.EnsembleModel == "" {
+		errs = append(errs, errors.New("api.ensemble_model must not be empty"))
+	}
+
+	if v := c.Forecast.CloudCoverThreshold; v != nil && (*v < 0 || *v > 100) {
+		errs = append(errs, fmt.Errorf("forecast.cloud_cover_threshold must be between 0 and 100, got %d", *v))
+	}
+	if v := c.Forecast.MinWindowHours; v != nil && (*v < 1 || *v > 24) {
+		errs = append(errs, fmt.Errorf("forecast.min_window_hours must be between 1 and 24, got %d", *v))
+	}
+	if v := c.Forecast.MaxMoonAltitude; v != nil && (*v < -90 || *v > 90) {
+		errs = append(errs, fmt.Errorf("forecast.max_moon_altitude must be between -90 and 90, got %g", *v))
+	}
+	if v := c.Forecast.MinTargetAltitude; v != nil && (*v < 0 || *v > 90) {
+		errs = append(errs, fmt.Errorf("forecast.min_target_altitude must be between 0 and 90, got %g", *v))
+	}
+	if v := c.Forecast.WindTolerance; v != nil {
+		if _, err := forecast.WindToleranceByName(*v); err != nil {
+			errs = append(errs, fmt.Errorf("forecast.wind_tolerance: %w", err))
+		}
+	}
+	if c.Forecast.TableHours < 1 {
+		errs = append(errs, fmt.Errorf("forecast.table_hours must be at least 1, got %d", c.Forecast.TableHours))
+	}
+
+	if c.Server.Address == "" {
+		errs = append(errs, errors.New("server.address must not be empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ComparisonModelList returns the weather models to compare side by side
+func (a APIConfig) ComparisonModelList() []string {
+	var models []string
+	for _, model := range strings.Split(a.ComparisonModels, ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// ResolveProfile returns the scoring profile to use for a place: the session override
+// if any, then the place's own profile, then the configured one. Thresholds set in the
+// configuration replace those of the profile.
+func (c Config) ResolveProfile(override string, place domain.Place) (forecast.Profile, error) {
+	name := c.Profile
+	switch {
+	case override != "":
+		name = override
+	case place.Profile != "":
+		name = place.Profile
+	}
+
+	profile, err := forecast.ProfileByName(name)
+	if err != nil {
+		return profile, err
+	}
+
+	if v := c.Forecast.CloudCoverThreshold; v != nil {
+		profile.Thresholds.CloudCover = *v
+	}
+	if v := c.Forecast.MinWindowHours; v != nil {
+		profile.Thresholds.MinWindowHours = *v
+	}
+	if v := c.Forecast.MaxMoonAltitude; v != nil {
+		profile.Thresholds.MaxMoonAltitude = *v
+	}
+	if v := c.Forecast.MinTargetAltitude; v != nil {
+		profile.Thresholds.MinTargetAltitude = *v
+	}
+	if v := c.Forecast.WindTolerance; v != nil {
+		if tolerance, err := forecast.WindToleranceByName(*v); err == nil {
+			profile.Thresholds.WindTolerance = tolerance
+		}
+	}
+
+	return profile, nil
+}
+
+// HasDefaultPlace reports whether a default place is configured
+func (c Config) HasDefaultPlace() bool {
+	return c.DefaultPlace.Favorite != "" || c.DefaultPlace.Latitude != nil
+}
+
+// ResolveDefaultPlace returns the configured default place, looking favorites up by name
+func (c Config) ResolveDefaultPlace(favorites *storage.FavoritesStore) (domain.Place, error) {
+	place := c.DefaultPlace
+
+	if place.Favorite != "" {
+		if fav, ok := favorites.FindFavorite(place.Favorite); ok {
+			return fav, nil
+		}
+		return domain.Place{}, fmt.Errorf("default_place.favorite: no favorite named '%s'", place.Favorite)
+	}
+
+	if place.Latitude == nil {
+		return domain.Place{}, errors.New("no default place configured")
+	}
+
+	name := place.Name
+	if name == "" {
+		name = fmt.Sprintf("%.4f, %.4f", *place.Latitude, *place.Longitude)
+	}
+	return domain.Place{Name: name, Latitude: *place.Latitude, Longitude: *place.Longitude}, nil
+}
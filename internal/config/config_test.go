@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/forecast"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default().Validate() = %v, want nil", err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), FileName), false)
+	if err != nil {
+		t.Fatalf("Load of a missing file = %v, want nil", err)
+	}
+	if cfg.API.ArchiveURL != Default().API.ArchiveURL {
+		t.Errorf("api.archive_url = %q, want the default %q", cfg.API.ArchiveURL, Default().API.ArchiveURL)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown key", "colour = \"red\"\n"},
+		{"empty archive url", "[api]\narchive_url = \"\"\n"},
+		{"target altitude out of range", "[forecast]\nmin_target_altitude = 95\n"},
+		{"unknown wind tolerance", "[forecast]\nwind_tolerance = \"tent\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path, true); err == nil {
+				t.Error("Load returned no error")
+			}
+		})
+	}
+}
+
+func TestResolveProfileWindTolerance(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    forecast.WindTolerance
+	}{
+		{"unset keeps the profile's", "", forecast.DefaultWindTolerance},
+		{"set overrides the profile's", "[forecast]\nwind_tolerance = \"dome\"\n", forecast.WindToleranceDome},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			profile, err := cfg.ResolveProfile("", domain.Place{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.Thresholds.WindTolerance != tt.want {
+				t.Errorf("wind tolerance = %q, want %q", profile.Thresholds.WindTolerance, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+// AirQualityData represents the air-quality forecast response
+type AirQualityData struct {
+	Hourly      HourlyAirQuality      `json:"hourly"`
+	HourlyUnits HourlyAirQualityUnits `json:"hourly_units"`
+	Latitude    float64               `json:"latitude"`
+	Longitude   float64               `json:"longitude"`
+	Timezone    string                `json:"timezone"`
+}
+
+// HourlyAirQuality holds the aerosol forecast, nil where the model has no value
+type HourlyAirQuality struct {
+	Time                []string   `json:"time"`
+	AerosolOpticalDepth []*float64 `json:"aerosol_optical_depth"`
+	Dust                []*float64 `json:"dust"`
+	PM25                []*float64 `json:"pm2_5"`
+	PM10                []*float64 `json:"pm10"`
+}
+
+type HourlyAirQualityUnits struct {
+	AerosolOpticalDepth string `json:"aerosol_optical_depth"`
+	Dust                string `json:"dust"`
+	PM25                string `json:"pm2_5"`
+	PM10                string `json:"pm10"`
+}
@@ -1,17 +1,104 @@
 package astro
 
 import (
+	"math"
 	"time"
 
 	"github.com/sixdouglas/suncalc"
 )
 
-// SunInfo holds astronomical information about the sun
+// SunInfo holds astronomical information about the sun for a night.
+// Dusk and Dawn mark the astronomical twilight, when the sun crosses -18°.
+// Times the sun does not reach that night, at high latitudes, are zero.
 type SunInfo struct {
 	Sunset  time.Time
 	Dusk    time.Time
 	Dawn    time.Time
 	Sunrise time.Time
+
+	Darkness        Darkness
+	DarkestTime     time.Time // solar midnight, when the sun is lowest
+	DarkestAltitude float64   // altitude of the sun at DarkestTime, in degrees
+	DarkestBand     Interval  // period with the sun below Darkness.Limit(), zero under the midnight sun
+	PolarNight      bool      // the sun does not rise the following day
+}
+
+// Darkness is how dark the sky gets during a night, after the lowest altitude of the sun
+type Darkness int
+
+const (
+	DarknessAstronomical Darkness = iota // the sun goes below -18°
+	DarknessNautical                     // the sun goes below -12° but not -18°
+	DarknessCivil                        // the sun goes below -6° but not -12°
+	DarknessTwilight                     // the sun sets but stays above -6°
+	DarknessMidnightSun                  // the sun does not set
+)
+
+// sunsetAltitude is the altitude of the sun's centre at sunset, accounting for refraction
+const sunsetAltitude = -0.833
+
+// String returns the identifier of the darkness
+func (d Darkness) String() string {
+	switch d {
+	case DarknessNautical:
+		return "nautical"
+	case DarknessCivil:
+		return "civil"
+	case DarknessTwilight:
+		return "twilight"
+	case DarknessMidnightSun:
+		return "midnight_sun"
+	default:
+		return "astronomical"
+	}
+}
+
+// Limit returns the sun altitude in degrees under which the sky is as dark as it gets
+func (d Darkness) Limit() float64 {
+	switch d {
+	case DarknessNautical:
+		return -12
+	case DarknessCivil:
+		return -6
+	case DarknessTwilight, DarknessMidnightSun:
+		return sunsetAltitude
+	default:
+		return AstronomicalNightAltitude
+	}
+}
+
+// darknessAt returns the darkness of a night whose lowest sun altitude is altitude
+func darknessAt(altitude float64) Darkness {
+	switch {
+	case altitude < AstronomicalNightAltitude:
+		return DarknessAstronomical
+	case altitude < -12:
+		return DarknessNautical
+	case altitude < -6:
+		return DarknessCivil
+	case altitude < sunsetAltitude:
+		return DarknessTwilight
+	default:
+		return DarknessMidnightSun
+	}
+}
+
+// Night returns the interval from sunset to sunrise. When the sun does not set or does not
+// rise, it is the day around solar midnight.
+func (s SunInfo) Night() Interval {
+	if s.Sunset.IsZero() || s.Sunrise.IsZero() {
+		return Interval{Start: s.DarkestTime.Add(-12 * time.Hour), End: s.DarkestTime.Add(12 * time.Hour)}
+	}
+	return Interval{Start: s.Sunset, End: s.Sunrise}
+}
+
+// AstronomicalNight returns the interval from astronomical dusk to dawn,
+// or false when the sun does not go below -18° that night
+func (s SunInfo) AstronomicalNight() (Interval, bool) {
+	if s.Dusk.IsZero() || s.Dawn.IsZero() {
+		return Interval{}, false
+	}
+	return Interval{Start: s.Dusk, End: s.Dawn}, true
 }
 
 // MoonInfo holds astronomical information about the moon
@@ -21,6 +108,20 @@ type MoonInfo struct {
 	Illumination float64
 	Moonrise     time.Time
 	Moonset      time.Time
+	Up           []Interval // periods above the horizon during the following 24 hours
+}
+
+// MoonPosition holds the position and illumination of the moon at a given time
+type MoonPosition struct {
+	Altitude     float64 // degrees above the horizon
+	Azimuth      float64 // degrees clockwise from north
+	Illumination float64 // percent of the disc lit
+	PhaseEmoji   string
+}
+
+// Up reports whether the moon is above the horizon
+func (p MoonPosition) Up() bool {
+	return p.Altitude > 0
 }
 
 // MoonPhaseInfo contains the name and emoji for a moon phase
@@ -29,26 +130,65 @@ type moonPhaseInfo struct {
 	emoji string
 }
 
-// GetSunInfo calculates sun-related astronomical information
-func GetSunInfo(lat, lon float64) SunInfo {
-	today := time.Now()
+// ObserverTime returns the time at which to look at the night starting on the calendar day
+// of date, in the site's time zone loc: now for tonight, noon for the nights ahead.
+// The zero date is tonight.
+func ObserverTime(date time.Time, loc *time.Location, now time.Time) time.Time {
+	now = now.In(loc)
+	if date.IsZero() {
+		return now
+	}
+
+	year, month, day := date.Date()
+	if y, m, d := now.Date(); y == year && m == month && d == day {
+		return now
+	}
+	return time.Date(year, month, day, 12, 0, 0, 0, loc)
+}
+
+// GetSunInfo calculates sun-related astronomical information for the night starting on the
+// calendar day of date. Times are in the location of date.
+func GetSunInfo(lat, lon float64, date time.Time) SunInfo {
+	year, month, day := date.Date()
+	today := time.Date(year, month, day, 12, 0, 0, 0, date.Location())
 	tomorrow := today.AddDate(0, 0, 1)
 
-	observer := suncalc.Observer{Latitude: lat, Longitude: lon, Location: time.Local}
+	observer := suncalc.Observer{Latitude: lat, Longitude: lon, Location: date.Location()}
 	sunTimes := suncalc.GetTimesWithObserver(today, observer)
 	sunTimesTomorrow := suncalc.GetTimesWithObserver(tomorrow, observer)
 
-	return SunInfo{
+	sunInfo := SunInfo{
 		Sunset:  sunTimes[suncalc.Sunset].Value,
-		Dusk:    sunTimes[suncalc.NauticalDusk].Value,
-		Dawn:    sunTimesTomorrow[suncalc.NauticalDawn].Value,
+		Dusk:    sunTimes[suncalc.Night].Value,
+		Dawn:    sunTimesTomorrow[suncalc.NightEnd].Value,
 		Sunrise: sunTimesTomorrow[suncalc.Sunrise].Value,
 	}
+
+	// Solar midnight is half a day after solar noon
+	solarNoon := sunTimes[suncalc.SolarNoon].Value
+	sunInfo.DarkestTime = solarNoon.Add(12 * time.Hour)
+	sunInfo.DarkestAltitude = sunAltitude(sunInfo.DarkestTime, lat, lon)
+	sunInfo.Darkness = darknessAt(sunInfo.DarkestAltitude)
+	sunInfo.PolarNight = sunInfo.Sunrise.IsZero() && sunAltitude(sunInfo.DarkestTime.Add(12*time.Hour), lat, lon) < sunsetAltitude
+
+	if sunInfo.Darkness != DarknessMidnightSun {
+		limit := sunInfo.Darkness.Limit()
+		for _, band := range Scan(sunInfo.Night(), func(t time.Time) bool {
+			return sunAltitude(t, lat, lon) < limit
+		}) {
+			if band.Duration() > sunInfo.DarkestBand.Duration() {
+				sunInfo.DarkestBand = band
+			}
+		}
+	}
+
+	return sunInfo
 }
 
-// GetMoonInfo calculates moon-related astronomical information
-func GetMoonInfo(lat, lon float64) MoonInfo {
-	today := time.Now()
+// GetMoonInfo calculates moon-related astronomical information at the given time.
+// Rise and set are those of the calendar day of date in its location.
+func GetMoonInfo(lat, lon float64, date time.Time) MoonInfo {
+	today := date
 	// tomorrow := today.AddDate(0, 0, 1)
 
 	moonTimes := suncalc.GetMoonTimes(today, lat, lon, false)
@@ -63,6 +203,23 @@ func GetMoonInfo(lat, lon float64) MoonInfo {
 		Illumination: illumination,
 		Moonrise:     moonTimes.Rise,
 		Moonset:      moonTimes.Set,
+		Up: Scan(Interval{Start: today, End: today.Add(24 * time.Hour)}, func(t time.Time) bool {
+			return moonAltitude(t, lat, lon) > 0
+		}),
+	}
+}
+
+// GetMoonPositionAt calculates the position and illumination of the moon at the given time
+func GetMoonPositionAt(lat, lon float64, t time.Time) MoonPosition {
+	position := suncalc.GetMoonPosition(t, lat, lon)
+	phase := suncalc.GetMoonIllumination(t)
+
+	return MoonPosition{
+		Altitude: position.Altitude * 180 / math.Pi,
+		// suncalc measures the azimuth from the south, westwards
+		Azimuth:      math.Mod(position.Azimuth*180/math.Pi+540, 360),
+		Illumination: phase.Fraction * 100,
+		PhaseEmoji:   getMoonPhaseInfo(phase).emoji,
 	}
 }
 
@@ -0,0 +1,169 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestGetSunInfoDarkness(t *testing.T) {
+	tests := []struct {
+		name           string
+		lat, lon       float64
+		date           time.Time
+		wantDarkness   Darkness
+		wantSunset     bool
+		wantSunrise    bool
+		wantDusk       bool
+		wantPolarNight bool
+	}{
+		{"Paris in winter", 48.85, 2.35, time.Date(2024, time.January, 11, 12, 0, 0, 0, time.UTC), DarknessAstronomical, true, true, true, false},
+		{"Paris at the solstice", 48.85, 2.35, time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC), DarknessNautical, true, true, false, false},
+		{"Oslo at the solstice", 59.91, 10.75, time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC), DarknessCivil, true, true, false, false},
+		{"Trondheim at the solstice", 63.43, 10.39, time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC), DarknessTwilight, true, true, false, false},
+		{"Tromsø midnight sun", 69.65, 18.96, time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC), DarknessMidnightSun, false, false, false, false},
+		{"Tromsø polar night", 69.65, 18.96, time.Date(2024, time.December, 21, 12, 0, 0, 0, time.UTC), DarknessAstronomical, false, false, true, true},
+		{"Svalbard polar night", 78.22, 15.65, time.Date(2024, time.December, 21, 12, 0, 0, 0, time.UTC), DarknessAstronomical, false, false, true, true},
+		{"Antarctic midnight sun", -77.85, 166.67, time.Date(2024, time.December, 21, 12, 0, 0, 0, time.UTC), DarknessMidnightSun, false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunInfo := GetSunInfo(tt.lat, tt.lon, tt.date)
+
+			if sunInfo.Darkness != tt.wantDarkness {
+				t.Errorf("Darkness = %v (lowest sun %.1f°), want %v", sunInfo.Darkness, sunInfo.DarkestAltitude, tt.wantDarkness)
+			}
+			if sunInfo.Sunset.IsZero() == tt.wantSunset {
+				t.Errorf("Sunset = %v, want set %v", sunInfo.Sunset, tt.wantSunset)
+			}
+			if sunInfo.Sunrise.IsZero() == tt.wantSunrise {
+				t.Errorf("Sunrise = %v, want set %v", sunInfo.Sunrise, tt.wantSunrise)
+			}
+			if sunInfo.Dusk.IsZero() == tt.wantDusk {
+				t.Errorf("Dusk = %v, want set %v", sunInfo.Dusk, tt.wantDusk)
+			}
+			if sunInfo.PolarNight != tt.wantPolarNight {
+				t.Errorf("PolarNight = %v, want %v", sunInfo.PolarNight, tt.wantPolarNight)
+			}
+			if darknessAt(sunInfo.DarkestAltitude) != sunInfo.Darkness {
+				t.Errorf("Darkness = %v, inconsistent with the lowest sun at %.1f°", sunInfo.Darkness, sunInfo.DarkestAltitude)
+			}
+
+			night := sunInfo.Night()
+			if !tt.wantSunset || !tt.wantSunrise {
+				if night.Duration() != 24*time.Hour || !night.Start.Add(12*time.Hour).Equal(sunInfo.DarkestTime) {
+					t.Errorf("Night = %v to %v, want the day around solar midnight %v", night.Start, night.End, sunInfo.DarkestTime)
+				}
+			}
+
+			band := sunInfo.DarkestBand
+			if tt.wantDarkness == DarknessMidnightSun {
+				if band.Duration() != 0 {
+					t.Errorf("DarkestBand = %v to %v, want none under the midnight sun", band.Start, band.End)
+				}
+				return
+			}
+			if band.Duration() <= 0 {
+				t.Fatal("DarkestBand is empty")
+			}
+			if band.Start.Before(night.Start) || band.End.After(night.End) {
+				t.Errorf("DarkestBand = %v to %v, outside the night %v to %v", band.Start, band.End, night.Start, night.End)
+			}
+			if band.Start.After(sunInfo.DarkestTime) || band.End.Before(sunInfo.DarkestTime) {
+				t.Errorf("DarkestBand = %v to %v, want it around solar midnight %v", band.Start, band.End, sunInfo.DarkestTime)
+			}
+			limit := sunInfo.Darkness.Limit()
+			for _, edge := range []time.Time{band.Start, band.End} {
+				if altitude := sunAltitude(edge, tt.lat, tt.lon); altitude >= limit {
+					t.Errorf("sun altitude at %v = %.2f°, want below %v°", edge, altitude, limit)
+				}
+			}
+			if tt.wantDusk && tt.wantSunset {
+				if d := band.Start.Sub(sunInfo.Dusk).Abs(); d > 2*time.Minute {
+					t.Errorf("DarkestBand starts %v from dusk, want within 2 minutes", d)
+				}
+				if d := band.End.Sub(sunInfo.Dawn).Abs(); d > 2*time.Minute {
+					t.Errorf("DarkestBand ends %v from dawn, want within 2 minutes", d)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSunInfoAcrossDaylightSaving(t *testing.T) {
+	paris := mustLoadLocation(t, "Europe/Paris")
+
+	tests := []struct {
+		name              string
+		date              time.Time
+		wantSunsetOffset  int
+		wantSunriseOffset int
+		wantSunsetDay     int
+		wantSunriseDay    int
+	}{
+		{"clocks forward", time.Date(2024, time.March, 30, 12, 0, 0, 0, paris), 3600, 7200, 30, 31},
+		{"clocks back", time.Date(2024, time.October, 26, 12, 0, 0, 0, paris), 7200, 3600, 26, 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunInfo := GetSunInfo(48.85, 2.35, tt.date)
+
+			if _, offset := sunInfo.Sunset.Zone(); offset != tt.wantSunsetOffset {
+				t.Errorf("Sunset %v offset = %d, want %d", sunInfo.Sunset, offset, tt.wantSunsetOffset)
+			}
+			if _, offset := sunInfo.Sunrise.Zone(); offset != tt.wantSunriseOffset {
+				t.Errorf("Sunrise %v offset = %d, want %d", sunInfo.Sunrise, offset, tt.wantSunriseOffset)
+			}
+			if sunInfo.Sunset.Day() != tt.wantSunsetDay || sunInfo.Sunrise.Day() != tt.wantSunriseDay {
+				t.Errorf("night from day %d to %d, want %d to %d", sunInfo.Sunset.Day(), sunInfo.Sunrise.Day(), tt.wantSunsetDay, tt.wantSunriseDay)
+			}
+
+			// The same night computed in UTC gives the same instants
+			year, month, day := tt.date.Date()
+			utc := GetSunInfo(48.85, 2.35, time.Date(year, month, day, 12, 0, 0, 0, time.UTC))
+			for _, pair := range [][2]time.Time{{sunInfo.Sunset, utc.Sunset}, {sunInfo.Dusk, utc.Dusk}, {sunInfo.Dawn, utc.Dawn}, {sunInfo.Sunrise, utc.Sunrise}} {
+				if d := pair[0].Sub(pair[1]).Abs(); d > time.Minute {
+					t.Errorf("%v differs from %v in UTC by %v", pair[0], pair[1], d)
+				}
+			}
+			if d := sunInfo.DarkestBand.Duration() - utc.DarkestBand.Duration(); d.Abs() > 2*time.Minute {
+				t.Errorf("DarkestBand lasts %v, %v in UTC", sunInfo.DarkestBand.Duration(), utc.DarkestBand.Duration())
+			}
+		})
+	}
+}
+
+func TestObserverTime(t *testing.T) {
+	paris := mustLoadLocation(t, "Europe/Paris")
+	now := time.Date(2024, time.March, 30, 22, 30, 0, 0, time.UTC) // 23:30 in Paris
+
+	tests := []struct {
+		name string
+		date time.Time
+		want time.Time
+	}{
+		{"tonight", time.Time{}, now},
+		{"today", time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC), now},
+		{"day of the clock change", time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 31, 12, 0, 0, 0, paris)},
+		{"next week", time.Date(2024, time.April, 6, 0, 0, 0, 0, time.UTC), time.Date(2024, time.April, 6, 12, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ObserverTime(tt.date, paris, now)
+			if !got.Equal(tt.want) || got.Location() != paris {
+				t.Errorf("ObserverTime = %v, want %v in Europe/Paris", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+// j2000 is the Julian day of the J2000.0 epoch, 2000-01-01 12:00 TT
+const j2000 = 2451545.0
+
+// Equatorial holds equatorial coordinates of the J2000 equinox, in degrees
+type Equatorial struct {
+	RightAscension float64
+	Declination    float64
+}
+
+// JulianDay returns the Julian day of t
+func JulianDay(t time.Time) float64 {
+	return float64(t.UnixMilli())/86400000 + 2440587.5
+}
+
+// Horizontal returns the altitude and azimuth in degrees of a point of the sky seen from
+// lat, lon at time t. The azimuth is measured clockwise from north. Refraction is ignored.
+func Horizontal(position Equatorial, lat, lon float64, t time.Time) (altitude, azimuth float64) {
+	hourAngle := radians(localSiderealTime(t, lon) - position.RightAscension)
+	phi := radians(lat)
+	delta := radians(position.Declination)
+
+	altitude = math.Asin(math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Cos(hourAngle))
+	azimuth = math.Atan2(math.Sin(hourAngle), math.Cos(hourAngle)*math.Sin(phi)-math.Tan(delta)*math.Cos(phi))
+
+	return degrees(altitude), math.Mod(degrees(azimuth)+540, 360)
+}
+
+// HourAngle returns the hour angle in degrees of a point of the sky seen from longitude lon
+// at time t, between -180 and 180. It is zero when the point transits the meridian.
+func HourAngle(position Equatorial, lon float64, t time.Time) float64 {
+	return normalizeDegrees(localSiderealTime(t, lon) - position.RightAscension)
+}
+
+// Separation returns the angular distance in degrees between two points of the sky
+func Separation(a, b Equatorial) float64 {
+	ra1, dec1 := radians(a.RightAscension), radians(a.Declination)
+	ra2, dec2 := radians(b.RightAscension), radians(b.Declination)
+
+	cos := math.Sin(dec1)*math.Sin(dec2) + math.Cos(dec1)*math.Cos(dec2)*math.Cos(ra1-ra2)
+	return degrees(math.Acos(math.Max(-1, math.Min(1, cos))))
+}
+
+// MoonEquatorial returns the approximate geocentric equatorial coordinates of the moon at t,
+// to within a degree, which is enough to tell how far it shines from a point of the sky
+func MoonEquatorial(t time.Time) Equatorial {
+	days := JulianDay(t) - j2000
+	meanLongitude := radians(218.316 + 13.176396*days)
+	meanAnomaly := radians(134.963 + 13.064993*days)
+	meanDistance := radians(93.272 + 13.229350*days)
+
+	longitude := meanLongitude + radians(6.289)*math.Sin(meanAnomaly)
+	latitude := radians(5.128) * math.Sin(meanDistance)
+	obliquity := radians(23.4397)
+
+	rightAscension := math.Atan2(math.Sin(longitude)*math.Cos(obliquity)-math.Tan(latitude)*math.Sin(obliquity), math.Cos(longitude))
+	declination := math.Asin(math.Sin(latitude)*math.Cos(obliquity) + math.Cos(latitude)*math.Sin(obliquity)*math.Sin(longitude))
+
+	return Equatorial{
+		RightAscension: math.Mod(degrees(rightAscension)+360, 360),
+		Declination:    degrees(declination),
+	}
+}
+
+// localSiderealTime returns the mean sidereal time at longitude lon in degrees
+func localSiderealTime(t time.Time, lon float64) float64 {
+	return math.Mod(280.46061837+360.98564736629*(JulianDay(t)-j2000)+lon, 360)
+}
+
+// normalizeDegrees brings an angle between -180 and 180 degrees
+func normalizeDegrees(angle float64) float64 {
+	angle = math.Mod(angle+180, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,115 @@
+package astro
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sixdouglas/suncalc"
+)
+
+// AstronomicalNightAltitude is the sun altitude in degrees below which the sky is fully dark
+const AstronomicalNightAltitude = -18
+
+// scanStep is the resolution of the altitude scans
+const scanStep = time.Minute
+
+// Interval is a span of time, possibly crossing midnight
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the interval
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Overlap returns how much of the span from start to end lies within the interval
+func (i Interval) Overlap(start, end time.Time) time.Duration {
+	if start.Before(i.Start) {
+		start = i.Start
+	}
+	if end.After(i.End) {
+		end = i.End
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// Intersect returns the spans of time covered by both a and b.
+// The intervals of each list must not overlap one another.
+func Intersect(a, b []Interval) []Interval {
+	var intervals []Interval
+	for _, x := range a {
+		for _, y := range b {
+			start, end := x.Start, x.End
+			if y.Start.After(start) {
+				start = y.Start
+			}
+			if y.End.Before(end) {
+				end = y.End
+			}
+			if end.After(start) {
+				intervals = append(intervals, Interval{Start: start, End: end})
+			}
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+	return intervals
+}
+
+// DarkWindows returns the parts of the night during which the sky is as dark as it gets and
+// the moon is below maxMoonAltitude degrees, with minute precision. The sky is dark when the sun
+// is below -18°, or in the darkest twilight band when it does not go that low.
+// There are none under the midnight sun.
+func DarkWindows(lat, lon float64, sunInfo SunInfo, maxMoonAltitude float64) []Interval {
+	if sunInfo.DarkestBand.Duration() <= 0 {
+		return nil
+	}
+
+	limit := sunInfo.Darkness.Limit()
+	return Scan(sunInfo.DarkestBand, func(t time.Time) bool {
+		return sunAltitude(t, lat, lon) < limit &&
+			moonAltitude(t, lat, lon) < maxMoonAltitude
+	})
+}
+
+// Scan returns the sub-intervals of within during which match holds, with minute precision
+func Scan(within Interval, match func(t time.Time) bool) []Interval {
+	var intervals []Interval
+	var current *Interval
+
+	for t := within.Start; !t.After(within.End); t = t.Add(scanStep) {
+		if match(t) {
+			if current == nil {
+				current = &Interval{Start: t}
+			}
+			current.End = t
+			continue
+		}
+		if current != nil {
+			intervals = append(intervals, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		intervals = append(intervals, *current)
+	}
+
+	return intervals
+}
+
+// sunAltitude returns the altitude of the sun in degrees
+func sunAltitude(t time.Time, lat, lon float64) float64 {
+	return suncalc.GetPosition(t, lat, lon).Altitude * 180 / math.Pi
+}
+
+// moonAltitude returns the altitude of the moon in degrees, corrected for refraction
+func moonAltitude(t time.Time, lat, lon float64) float64 {
+	return suncalc.GetMoonPosition(t, lat, lon).Altitude * 180 / math.Pi
+}
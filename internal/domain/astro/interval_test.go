@@ -0,0 +1,183 @@
+package astro
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns the time minutes after 2024-01-10 22:00 UTC
+func at(minutes int) time.Time {
+	return time.Date(2024, time.January, 10, 22, 0, 0, 0, time.UTC).Add(time.Duration(minutes) * time.Minute)
+}
+
+func span(start, end int) Interval {
+	return Interval{Start: at(start), End: at(end)}
+}
+
+func equalIntervals(a, b []Interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || !a[i].End.Equal(b[i].End) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestScan(t *testing.T) {
+	// minutes returns a match holding during the given spans of minutes, ends included
+	minutes := func(spans ...[2]int) func(time.Time) bool {
+		return func(t time.Time) bool {
+			for _, s := range spans {
+				if !t.Before(at(s[0])) && !t.After(at(s[1])) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	tests := []struct {
+		name  string
+		match func(time.Time) bool
+		want  []Interval
+	}{
+		{name: "never", match: minutes(), want: nil},
+		{name: "always", match: minutes([2]int{-10, 200}), want: []Interval{span(0, 120)}},
+		{name: "inside", match: minutes([2]int{30, 45}), want: []Interval{span(30, 45)}},
+		{name: "single minute", match: minutes([2]int{60, 60}), want: []Interval{span(60, 60)}},
+		{name: "from the start", match: minutes([2]int{-5, 20}), want: []Interval{span(0, 20)}},
+		{name: "until the end", match: minutes([2]int{100, 130}), want: []Interval{span(100, 120)}},
+		{name: "two runs across midnight", match: minutes([2]int{10, 70}, [2]int{90, 110}), want: []Interval{span(10, 70), span(90, 110)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scan(span(0, 120), tt.match); !equalIntervals(got, tt.want) {
+				t.Errorf("Scan = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []Interval
+		want []Interval
+	}{
+		{name: "empty", a: nil, b: []Interval{span(0, 60)}, want: nil},
+		{name: "disjoint", a: []Interval{span(0, 30)}, b: []Interval{span(40, 60)}, want: nil},
+		{name: "touching", a: []Interval{span(0, 30)}, b: []Interval{span(30, 60)}, want: nil},
+		{name: "overlapping", a: []Interval{span(0, 40)}, b: []Interval{span(20, 60)}, want: []Interval{span(20, 40)}},
+		{name: "contained", a: []Interval{span(0, 120)}, b: []Interval{span(30, 45)}, want: []Interval{span(30, 45)}},
+		{
+			name: "several, sorted",
+			a:    []Interval{span(60, 120), span(0, 30)},
+			b:    []Interval{span(10, 70), span(100, 200)},
+			want: []Interval{span(10, 30), span(60, 70), span(100, 120)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Intersect(tt.a, tt.b); !equalIntervals(got, tt.want) {
+				t.Errorf("Intersect = %v, want %v", got, tt.want)
+			}
+			if got := Intersect(tt.b, tt.a); !equalIntervals(got, tt.want) {
+				t.Errorf("Intersect reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	interval := span(0, 60)
+	tests := []struct {
+		name       string
+		start, end int
+		want       time.Duration
+	}{
+		{name: "inside", start: 10, end: 20, want: 10 * time.Minute},
+		{name: "across the start", start: -30, end: 15, want: 15 * time.Minute},
+		{name: "across the end", start: 50, end: 90, want: 10 * time.Minute},
+		{name: "around", start: -10, end: 90, want: time.Hour},
+		{name: "after", start: 70, end: 90, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interval.Overlap(at(tt.start), at(tt.end)); got != tt.want {
+				t.Errorf("Overlap = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDarkWindows(t *testing.T) {
+	const lat, lon = 48.85, 2.35
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		date            time.Time
+		maxMoonAltitude float64
+		wantWholeBand   bool
+	}{
+		// new moon on 2024-01-11, below the horizon all night
+		{name: "new moon", date: time.Date(2024, time.January, 11, 12, 0, 0, 0, paris), maxMoonAltitude: 0, wantWholeBand: true},
+		{name: "full moon allowed", date: time.Date(2024, time.January, 25, 12, 0, 0, 0, paris), maxMoonAltitude: 90, wantWholeBand: true},
+		// first quarter on 2024-01-18, setting around 01:00
+		{name: "first quarter", date: time.Date(2024, time.January, 18, 12, 0, 0, 0, paris), maxMoonAltitude: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sunInfo := GetSunInfo(lat, lon, tt.date)
+			windows := DarkWindows(lat, lon, sunInfo, tt.maxMoonAltitude)
+
+			var total time.Duration
+			for _, window := range windows {
+				total += window.Duration()
+				if window.Start.Before(sunInfo.DarkestBand.Start) || window.End.After(sunInfo.DarkestBand.End) {
+					t.Errorf("window %v to %v outside the darkest band", window.Start, window.End)
+				}
+				for _, edge := range []time.Time{window.Start, window.End} {
+					if altitude := sunAltitude(edge, lat, lon); altitude >= AstronomicalNightAltitude {
+						t.Errorf("sun altitude at %v = %.2f°, want below %d°", edge, altitude, AstronomicalNightAltitude)
+					}
+					if altitude := moonAltitude(edge, lat, lon); altitude >= tt.maxMoonAltitude {
+						t.Errorf("moon altitude at %v = %.2f°, want below %v°", edge, altitude, tt.maxMoonAltitude)
+					}
+				}
+			}
+
+			band := sunInfo.DarkestBand.Duration()
+			if tt.wantWholeBand && (len(windows) != 1 || total != band) {
+				t.Errorf("windows = %v, want the whole darkest band of %v", windows, band)
+			}
+			if !tt.wantWholeBand && (total == 0 || total >= band) {
+				t.Errorf("windows last %v, want part of the darkest band of %v", total, band)
+			}
+		})
+	}
+
+	t.Run("full moon leaves no window", func(t *testing.T) {
+		sunInfo := GetSunInfo(lat, lon, time.Date(2024, time.January, 25, 12, 0, 0, 0, paris))
+		if windows := DarkWindows(lat, lon, sunInfo, 0); len(windows) != 0 {
+			t.Errorf("DarkWindows = %v, want none with the full moon up all night", windows)
+		}
+	})
+
+	t.Run("midnight sun", func(t *testing.T) {
+		sunInfo := GetSunInfo(69.65, 18.96, time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC))
+		if windows := DarkWindows(69.65, 18.96, sunInfo, 90); windows != nil {
+			t.Errorf("DarkWindows = %v, want nil under the midnight sun", windows)
+		}
+	})
+}
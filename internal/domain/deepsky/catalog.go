@@ -0,0 +1,152 @@
+package deepsky
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// catalogData lists the Messier and Caldwell objects with their J2000 coordinates
+//
+//go:embed catalog.csv
+var catalogData string
+
+// catalog holds the parsed objects, in the order of the Messier then Caldwell numbers
+var catalog = mustParseCatalog(catalogData)
+
+// ObjectType is the kind of a deep-sky object
+type ObjectType string
+
+const (
+	Galaxy           ObjectType = "galaxy"
+	GlobularCluster  ObjectType = "globular_cluster"
+	OpenCluster      ObjectType = "open_cluster"
+	Nebula           ObjectType = "nebula"
+	PlanetaryNebula  ObjectType = "planetary_nebula"
+	SupernovaRemnant ObjectType = "supernova_remnant"
+	DarkNebula       ObjectType = "dark_nebula"
+	StarCloud        ObjectType = "star_cloud"
+	Asterism         ObjectType = "asterism"
+	DoubleStar       ObjectType = "double_star"
+)
+
+// Object is an entry of the deep-sky catalogue
+type Object struct {
+	ID            string // Messier or Caldwell number, such as M31 or C14
+	Designation   string // NGC, IC or other catalogue designation, empty when there is none
+	Name          string // common name, empty when there is none
+	Type          ObjectType
+	Constellation string // IAU abbreviation
+	Position      astro.Equatorial
+	Magnitude     float64 // apparent visual magnitude, NaN when unknown
+	Size          float64 // apparent size along the major axis, in arcminutes
+}
+
+// Label returns the common name of the object, or its designation when it has none
+func (o Object) Label() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.Designation
+}
+
+// Catalog returns every object of the embedded catalogue
+func Catalog() []Object {
+	return append([]Object(nil), catalog...)
+}
+
+// mustParseCatalog parses the embedded catalogue, which is known to be valid
+func mustParseCatalog(data string) []Object {
+	objects, err := parseCatalog(data)
+	if err != nil {
+		panic(err)
+	}
+	return objects
+}
+
+// parseCatalog reads the objects from CSV records of id, designation, name, type,
+// constellation, right ascension ("hh mm.m"), declination ("±dd mm"), magnitude and size
+func parseCatalog(data string) ([]Object, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalogue: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	objects := make([]Object, 0, len(records)-1)
+	for _, record := range records[1:] {
+		object, err := parseObject(record)
+		if err != nil {
+			return nil, fmt.Errorf("catalogue entry %s: %w", record[0], err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// parseObject reads a catalogue record
+func parseObject(record []string) (Object, error) {
+	if len(record) != 9 {
+		return Object{}, fmt.Errorf("expected 9 fields, got %d", len(record))
+	}
+
+	rightAscension, err := parseSexagesimal(record[5])
+	if err != nil {
+		return Object{}, fmt.Errorf("invalid right ascension: %w", err)
+	}
+	declination, err := parseSexagesimal(record[6])
+	if err != nil {
+		return Object{}, fmt.Errorf("invalid declination: %w", err)
+	}
+
+	magnitude := math.NaN()
+	if record[7] != "" {
+		if magnitude, err = strconv.ParseFloat(record[7], 64); err != nil {
+			return Object{}, fmt.Errorf("invalid magnitude: %w", err)
+		}
+	}
+	size, err := strconv.ParseFloat(record[8], 64)
+	if err != nil {
+		return Object{}, fmt.Errorf("invalid size: %w", err)
+	}
+
+	return Object{
+		ID:            record[0],
+		Designation:   record[1],
+		Name:          record[2],
+		Type:          ObjectType(record[3]),
+		Constellation: record[4],
+		Position:      astro.Equatorial{RightAscension: rightAscension * 15, Declination: declination},
+		Magnitude:     magnitude,
+		Size:          size,
+	}, nil
+}
+
+// parseSexagesimal reads a signed "units minutes" value such as "-05 27" or "05 34.5"
+func parseSexagesimal(value string) (float64, error) {
+	units, minutes, ok := strings.Cut(value, " ")
+	if !ok {
+		return 0, fmt.Errorf("expected units and minutes, got %q", value)
+	}
+
+	whole, err := strconv.ParseFloat(units, 64)
+	if err != nil {
+		return 0, err
+	}
+	fraction, err := strconv.ParseFloat(minutes, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if strings.HasPrefix(units, "-") {
+		return whole - fraction/60, nil
+	}
+	return whole + fraction/60, nil
+}
@@ -0,0 +1,152 @@
+package deepsky
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	objects := Catalog()
+	if len(objects) != 219 {
+		t.Fatalf("len(Catalog()) = %d, want 219", len(objects))
+	}
+
+	counts := map[byte]int{}
+	seen := map[string]bool{}
+	for _, object := range objects {
+		if seen[object.ID] {
+			t.Errorf("%s listed twice", object.ID)
+		}
+		seen[object.ID] = true
+		counts[object.ID[0]]++
+
+		if ra := object.Position.RightAscension; ra < 0 || ra >= 360 {
+			t.Errorf("%s right ascension = %v, want within [0, 360)", object.ID, ra)
+		}
+		if dec := object.Position.Declination; dec < -90 || dec > 90 {
+			t.Errorf("%s declination = %v, want within [-90, 90]", object.ID, dec)
+		}
+		if object.Label() == "" {
+			t.Errorf("%s has neither a name nor a designation", object.ID)
+		}
+	}
+	if counts['M'] != 110 || counts['C'] != 109 {
+		t.Errorf("Messier, Caldwell objects = %d, %d, want 110, 109", counts['M'], counts['C'])
+	}
+
+	objects[0].ID = "changed"
+	if Catalog()[0].ID == "changed" {
+		t.Error("Catalog() returned the catalogue itself, want a copy")
+	}
+}
+
+func TestParseSexagesimal(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "05 34.5", want: 5.575},
+		{value: "+22 01", want: 22 + 1.0/60},
+		{value: "-05 27", want: -5.45},
+		{value: "-00 49", want: -49.0 / 60},
+		{value: "00 00", want: 0},
+		{value: "0534", wantErr: true},
+		{value: "ab 12", wantErr: true},
+		{value: "05 xx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSexagesimal(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseSexagesimal(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSexagesimal(%q) failed: %v", tt.value, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("parseSexagesimal(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseObject(t *testing.T) {
+	record := strings.Split("M2,NGC 7089,,globular_cluster,Aqr,21 33.5,-00 49,6.5,16", ",")
+	object, err := parseObject(record)
+	if err != nil {
+		t.Fatalf("parseObject failed: %v", err)
+	}
+	if object.ID != "M2" || object.Designation != "NGC 7089" || object.Name != "" ||
+		object.Type != GlobularCluster || object.Constellation != "Aqr" {
+		t.Errorf("parseObject = %+v, want M2, NGC 7089, a globular cluster in Aqr", object)
+	}
+	if math.Abs(object.Position.RightAscension-(21+33.5/60)*15) > 1e-9 {
+		t.Errorf("right ascension = %v, want %v", object.Position.RightAscension, (21+33.5/60)*15)
+	}
+	if math.Abs(object.Position.Declination+49.0/60) > 1e-9 {
+		t.Errorf("declination = %v, want %v", object.Position.Declination, -49.0/60)
+	}
+	if object.Magnitude != 6.5 || object.Size != 16 {
+		t.Errorf("magnitude, size = %v, %v, want 6.5, 16", object.Magnitude, object.Size)
+	}
+	if object.Label() != "NGC 7089" {
+		t.Errorf("Label() = %q, want the designation", object.Label())
+	}
+
+	record[7] = ""
+	if object, err = parseObject(record); err != nil {
+		t.Fatalf("parseObject without magnitude failed: %v", err)
+	}
+	if !math.IsNaN(object.Magnitude) {
+		t.Errorf("magnitude = %v, want NaN when missing", object.Magnitude)
+	}
+}
+
+func TestParseObjectErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+	}{
+		{name: "missing field", record: "M2,NGC 7089,,globular_cluster,Aqr,21 33.5,-00 49,6.5"},
+		{name: "extra field", record: "M2,NGC 7089,,globular_cluster,Aqr,21 33.5,-00 49,6.5,16,x"},
+		{name: "right ascension", record: "M2,NGC 7089,,globular_cluster,Aqr,2133.5,-00 49,6.5,16"},
+		{name: "declination", record: "M2,NGC 7089,,globular_cluster,Aqr,21 33.5,south,6.5,16"},
+		{name: "magnitude", record: "M2,NGC 7089,,globular_cluster,Aqr,21 33.5,-00 49,bright,16"},
+		{name: "size", record: "M2,NGC 7089,,globular_cluster,Aqr,21 33.5,-00 49,6.5,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseObject(strings.Split(tt.record, ",")); err == nil {
+				t.Errorf("parseObject(%q) succeeded, want an error", tt.record)
+			}
+		})
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	header := "id,designation,name,type,constellation,ra,dec,magnitude,size\n"
+
+	objects, err := parseCatalog(header + "M1,NGC 1952,Crab Nebula,supernova_remnant,Tau,05 34.5,+22 01,8.4,6\n")
+	if err != nil {
+		t.Fatalf("parseCatalog failed: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Label() != "Crab Nebula" {
+		t.Errorf("parseCatalog = %+v, want the Crab Nebula alone", objects)
+	}
+
+	_, err = parseCatalog(header + "M1,NGC 1952,Crab Nebula,supernova_remnant,Tau,05 34.5,+22 01,8.4,x\n")
+	if err == nil || !strings.Contains(err.Error(), "M1") {
+		t.Errorf("parseCatalog error = %v, want one naming M1", err)
+	}
+
+	if _, err := parseCatalog(header + "M1,\"NGC 1952\n"); err == nil {
+		t.Error("parseCatalog of malformed CSV succeeded, want an error")
+	}
+}
@@ -0,0 +1,131 @@
+package deepsky
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// DefaultCount is the number of targets suggested for a night
+const DefaultCount = 10
+
+// moonReach is the separation in degrees beyond which the moon no longer washes an object out
+const moonReach = 90
+
+// siderealDay is the rotation of the sky in degrees per day
+const siderealDay = 360.98564736629
+
+// Target is a catalogue object standing high enough during the windows of a night
+type Target struct {
+	Object
+	Up              []astro.Interval // periods above the altitude limit within the windows
+	Transit         time.Time        // meridian crossing nearest to the middle of the windows
+	TransitAltitude float64          // altitude at transit, in degrees
+	MoonSeparation  float64          // angular distance from the moon when the object is best placed, in degrees
+	MoonUp          bool             // whether the moon is above the horizon then
+	Score           float64
+}
+
+// Hours returns how long the target stays above the altitude limit, in hours
+func (t Target) Hours() float64 {
+	var duration time.Duration
+	for _, interval := range t.Up {
+		duration += interval.Duration()
+	}
+	return duration.Hours()
+}
+
+// BestTargets ranks the catalogue objects standing above minAltitude degrees during windows,
+// seen from lat, lon, and returns at most count of them. Every hour above the limit counts
+// one point, and transiting within these periods up to one more the closer to the middle of
+// the windows. A moon above the horizon takes away a share of the total growing with its
+// illumination and its closeness to the object. Brighter objects come first on a tie, and
+// those of unknown magnitude last.
+func BestTargets(lat, lon float64, windows []astro.Interval, minAltitude float64, count int) []Target {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	loc := windows[0].Start.Location()
+	span := windows[len(windows)-1].End.Sub(windows[0].Start)
+	middle := windows[0].Start.Add(span / 2)
+
+	var targets []Target
+	for _, object := range catalog {
+		var up []astro.Interval
+		for _, window := range windows {
+			up = append(up, astro.Scan(window, func(t time.Time) bool {
+				altitude, _ := astro.Horizontal(object.Position, lat, lon, t)
+				return altitude >= minAltitude
+			})...)
+		}
+		if len(up) == 0 {
+			continue
+		}
+
+		target := Target{Object: object, Up: up, Transit: transitNear(object.Position, lon, middle).In(loc)}
+		target.TransitAltitude, _ = astro.Horizontal(object.Position, lat, lon, target.Transit)
+
+		best := closestTime(up, target.Transit)
+		moon := astro.GetMoonPositionAt(lat, lon, best)
+		target.MoonSeparation = astro.Separation(object.Position, astro.MoonEquatorial(best))
+		target.MoonUp = moon.Up()
+
+		target.Score = target.Hours()
+		if best.Equal(target.Transit) && span > 0 {
+			target.Score += math.Max(0, 1-2*target.Transit.Sub(middle).Abs().Hours()/span.Hours())
+		}
+		if target.MoonUp {
+			target.Score *= 1 - moon.Illumination/100*math.Max(0, 1-target.MoonSeparation/moonReach)
+		}
+
+		targets = append(targets, target)
+	}
+
+	rankTargets(targets)
+	if len(targets) > count {
+		targets = targets[:count]
+	}
+	return targets
+}
+
+// rankTargets sorts targets by decreasing score, then by increasing magnitude with unknown
+// magnitudes last, keeping the catalogue order otherwise
+func rankTargets(targets []Target) {
+	sort.SliceStable(targets, func(i, j int) bool {
+		a, b := targets[i], targets[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if aUnknown, bUnknown := math.IsNaN(a.Magnitude), math.IsNaN(b.Magnitude); aUnknown || bUnknown {
+			return !aUnknown && bUnknown
+		}
+		return a.Magnitude < b.Magnitude
+	})
+}
+
+// transitNear returns the meridian crossing of a point of the sky nearest to t, to the minute
+func transitNear(position astro.Equatorial, lon float64, t time.Time) time.Time {
+	hourAngle := astro.HourAngle(position, lon, t)
+	return t.Add(-time.Duration(hourAngle / siderealDay * float64(24*time.Hour))).Round(time.Minute)
+}
+
+// closestTime returns the moment within intervals closest to t
+func closestTime(intervals []astro.Interval, t time.Time) time.Time {
+	var closest time.Time
+	var distance time.Duration = math.MaxInt64
+	for _, interval := range intervals {
+		candidate := t
+		if candidate.Before(interval.Start) {
+			candidate = interval.Start
+		} else if candidate.After(interval.End) {
+			candidate = interval.End
+		}
+		if d := (candidate.Sub(t)).Abs(); d < distance {
+			closest, distance = candidate, d
+		}
+	}
+	return closest
+}
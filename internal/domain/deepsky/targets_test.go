@@ -0,0 +1,139 @@
+package deepsky
+
+import (
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// parisLat, parisLon are the site of the target tests, where objects culminate at 41.15° plus their declination
+const parisLat, parisLon = 48.85, 2.35
+
+// nightWindows returns a window from 20:00 to 04:00 UTC starting on the given day
+func nightWindows(year int, month time.Month, day int) []astro.Interval {
+	start := time.Date(year, month, day, 20, 0, 0, 0, time.UTC)
+	return []astro.Interval{{Start: start, End: start.Add(8 * time.Hour)}}
+}
+
+func TestBestTargetsWithoutWindows(t *testing.T) {
+	if targets := BestTargets(parisLat, parisLon, nil, 30, DefaultCount); targets != nil {
+		t.Errorf("BestTargets without windows = %v, want nil", targets)
+	}
+}
+
+func TestBestTargets(t *testing.T) {
+	// new moon on 2024-01-11
+	windows := nightWindows(2024, time.January, 11)
+	const minAltitude = 30
+
+	all := BestTargets(parisLat, parisLon, windows, minAltitude, len(catalog))
+	if len(all) == 0 || len(all) == len(catalog) {
+		t.Fatalf("len(BestTargets) = %d, want some but not all of the catalogue", len(all))
+	}
+
+	for _, target := range all {
+		if maxAltitude := 90 - parisLat + target.Position.Declination; maxAltitude < minAltitude {
+			t.Errorf("%s culminates at %.1f°, want it left out below %d°", target.ID, maxAltitude, minAltitude)
+		}
+		if len(target.Up) == 0 || target.Hours() > windows[0].Duration().Hours() {
+			t.Errorf("%s up for %.1f h, want within the %v window", target.ID, target.Hours(), windows[0].Duration())
+		}
+		for _, up := range target.Up {
+			if up.Start.Before(windows[0].Start) || up.End.After(windows[0].End) {
+				t.Errorf("%s up from %v to %v, want within the window", target.ID, up.Start, up.End)
+			}
+			for _, at := range []time.Time{up.Start, up.End} {
+				if altitude, _ := astro.Horizontal(target.Position, parisLat, parisLon, at); altitude < minAltitude {
+					t.Errorf("%s altitude at %v = %.2f°, want at least %d°", target.ID, at, altitude, minAltitude)
+				}
+			}
+		}
+		if target.MoonUp {
+			t.Errorf("%s has the moon up on a new moon night", target.ID)
+		}
+		if target.Score < target.Hours() || target.Score > target.Hours()+1 {
+			t.Errorf("%s score = %.2f, want between %.2f and %.2f without the moon", target.ID, target.Score, target.Hours(), target.Hours()+1)
+		}
+	}
+
+	if !sort.SliceIsSorted(all, func(i, j int) bool { return all[i].Score > all[j].Score }) {
+		t.Error("BestTargets not sorted by decreasing score")
+	}
+
+	best := BestTargets(parisLat, parisLon, windows, minAltitude, DefaultCount)
+	if len(best) != DefaultCount {
+		t.Fatalf("len(BestTargets) = %d, want %d", len(best), DefaultCount)
+	}
+	for i := range best {
+		if best[i].ID != all[i].ID {
+			t.Errorf("target %d = %s, want %s as in the full ranking", i, best[i].ID, all[i].ID)
+		}
+	}
+}
+
+func TestBestTargetsMoonPenalty(t *testing.T) {
+	// full moon on 2024-01-25, up all night
+	targets := BestTargets(parisLat, parisLon, nightWindows(2024, time.January, 25), 30, len(catalog))
+
+	near := 0
+	for _, target := range targets {
+		if !target.MoonUp || target.MoonSeparation >= moonReach {
+			continue
+		}
+		near++
+		if ceiling := (target.Hours() + 1) * (0.01 + target.MoonSeparation/moonReach); target.Score > ceiling {
+			t.Errorf("%s %.0f° from the full moon scores %.2f, want at most %.2f", target.ID, target.MoonSeparation, target.Score, ceiling)
+		}
+	}
+	if near == 0 {
+		t.Error("no target near the full moon, want some")
+	}
+}
+
+func TestRankTargets(t *testing.T) {
+	target := func(id string, score, magnitude float64) Target {
+		return Target{Object: Object{ID: id, Magnitude: magnitude}, Score: score}
+	}
+	nan := math.NaN()
+
+	tests := []struct {
+		name    string
+		targets []Target
+		want    []string
+	}{
+		{
+			name:    "score first",
+			targets: []Target{target("M1", 2, 5), target("M2", 3, 9), target("M3", 1, 1)},
+			want:    []string{"M2", "M1", "M3"},
+		},
+		{
+			name:    "brighter on a tie",
+			targets: []Target{target("M1", 2, 8), target("M2", 2, 4), target("M3", 2, 6)},
+			want:    []string{"M2", "M3", "M1"},
+		},
+		{
+			name:    "unknown magnitude last",
+			targets: []Target{target("C1", 2, nan), target("M2", 2, 9), target("C2", 2, nan), target("M3", 2, 4)},
+			want:    []string{"M3", "M2", "C1", "C2"},
+		},
+		{
+			name:    "unknown magnitude keeps catalogue order",
+			targets: []Target{target("C2", 2, nan), target("C1", 2, nan), target("M1", 1, nan)},
+			want:    []string{"C2", "C1", "M1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rankTargets(tt.targets)
+			for i, target := range tt.targets {
+				if target.ID != tt.want[i] {
+					t.Errorf("rank %d = %s, want %s", i, target.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+// EnsembleData holds the hourly cloud cover forecast of every member of an ensemble model
+type EnsembleData struct {
+	Model string   `json:"model"`
+	Time  []string `json:"time"`
+	// CloudCover holds one series per member, with a negative value where a member has no forecast
+	CloudCover [][]float64 `json:"cloud_cover_members"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// ForecastRecord is the hourly forecast of one model for a favorite, kept to verify it later
+type ForecastRecord struct {
+	Place       string    `json:"place"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Model       string    `json:"model"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	Timezone    string    `json:"timezone,omitempty"` // empty in records kept before it was stored
+	UTCOffset   int       `json:"utc_offset_seconds"`
+	Time        []string  `json:"time"`
+	CloudCover  []int     `json:"cloud_cover"`           // negative where the model has no forecast
+	Temperature []float64 `json:"temperature,omitempty"` // empty when only the cloud cover was fetched
+}
+
+// HistoricalWeather represents the archive API response with the observed hourly weather
+type HistoricalWeather struct {
+	Hourly    HistoricalHourly `json:"hourly"`
+	Latitude  float64          `json:"latitude"`
+	Longitude float64          `json:"longitude"`
+	Timezone  string           `json:"timezone"`
+	UTCOffset int              `json:"utc_offset_seconds"`
+}
+
+// HistoricalHourly holds the observed values, nil where the archive has no data yet
+type HistoricalHourly struct {
+	Time        []string   `json:"time"`
+	CloudCover  []*float64 `json:"cloud_cover"`
+	Temperature []*float64 `json:"temperature_2m"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+// ModelComparison holds the hourly cloud cover forecast of several weather models
+type ModelComparison struct {
+	Time      []string      `json:"time"`
+	Timezone  string        `json:"timezone"`
+	UTCOffset int           `json:"utc_offset_seconds"`
+	Models    []ModelSeries `json:"models"`
+}
+
+// ModelSeries is the hourly cloud cover forecast of one weather model,
+// with a negative value where the model has no forecast
+type ModelSeries struct {
+	Model      string `json:"model"`
+	CloudCover []int  `json:"cloud_cover"`
+}
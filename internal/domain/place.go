@@ -6,6 +6,7 @@ type Place struct {
 	Address   string
 	Latitude  float64
 	Longitude float64
+	Profile   string `json:",omitempty"` // scoring profile used for this place, if any
 }
 
 func (p Place) Title() string       { return p.Name }
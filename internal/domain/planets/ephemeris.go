@@ -0,0 +1,105 @@
+package planets
+
+import (
+	"math"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// obliquity is the tilt of the ecliptic on the equator at J2000, in degrees
+const obliquity = 23.43928
+
+// orbitalElements are the Keplerian elements of an orbit at J2000 and their rates per
+// Julian century, from E. M. Standish, "Keplerian Elements for Approximate Positions
+// of the Major Planets" (valid from 1800 to 2050, to a few minutes of arc)
+type orbitalElements struct {
+	a, e, i, l, perihelion, node                         float64 // AU and degrees
+	aRate, eRate, iRate, lRate, perihelionRate, nodeRate float64
+}
+
+// earth holds the orbital elements of the Earth-Moon barycenter
+var earth = orbitalElements{
+	1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0,
+	0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0,
+}
+
+// vector is a position in astronomical units
+type vector struct {
+	x, y, z float64
+}
+
+func (v vector) sub(w vector) vector {
+	return vector{v.x - w.x, v.y - w.y, v.z - w.z}
+}
+
+func (v vector) dot(w vector) float64 {
+	return v.x*w.x + v.y*w.y + v.z*w.z
+}
+
+func (v vector) length() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// position returns the heliocentric position on the J2000 ecliptic at t Julian centuries after J2000
+func (o orbitalElements) position(t float64) vector {
+	a := o.a + o.aRate*t
+	e := o.e + o.eRate*t
+	i := radians(o.i + o.iRate*t)
+	l := o.l + o.lRate*t
+	perihelion := o.perihelion + o.perihelionRate*t
+	node := radians(o.node + o.nodeRate*t)
+	argument := radians(perihelion) - node
+
+	meanAnomaly := radians(math.Mod(l-perihelion, 360))
+	anomaly := meanAnomaly
+	for range 8 {
+		anomaly -= (anomaly - e*math.Sin(anomaly) - meanAnomaly) / (1 - e*math.Cos(anomaly))
+	}
+
+	// Position in the orbital plane, the x axis pointing to the perihelion
+	x := a * (math.Cos(anomaly) - e)
+	y := a * math.Sqrt(1-e*e) * math.Sin(anomaly)
+
+	cosW, sinW := math.Cos(argument), math.Sin(argument)
+	cosN, sinN := math.Cos(node), math.Sin(node)
+	cosI, sinI := math.Cos(i), math.Sin(i)
+
+	return vector{
+		x: (cosW*cosN-sinW*sinN*cosI)*x + (-sinW*cosN-cosW*sinN*cosI)*y,
+		y: (cosW*sinN+sinW*cosN*cosI)*x + (-sinW*sinN+cosW*cosN*cosI)*y,
+		z: sinW*sinI*x + cosW*sinI*y,
+	}
+}
+
+// julianCenturies returns the time elapsed since J2000 in Julian centuries
+func julianCenturies(t time.Time) float64 {
+	return (astro.JulianDay(t) - 2451545.0) / 36525
+}
+
+// equatorial converts a position on the J2000 ecliptic to equatorial coordinates
+func equatorial(v vector) astro.Equatorial {
+	eps := radians(obliquity)
+	x := v.x
+	y := v.y*math.Cos(eps) - v.z*math.Sin(eps)
+	z := v.y*math.Sin(eps) + v.z*math.Cos(eps)
+
+	return astro.Equatorial{
+		RightAscension: math.Mod(degrees(math.Atan2(y, x))+360, 360),
+		Declination:    degrees(math.Atan2(z, math.Hypot(x, y))),
+	}
+}
+
+// unit returns the unit vector pointing to equatorial coordinates
+func unit(position astro.Equatorial) vector {
+	ra, dec := radians(position.RightAscension), radians(position.Declination)
+	return vector{math.Cos(dec) * math.Cos(ra), math.Cos(dec) * math.Sin(ra), math.Sin(dec)}
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
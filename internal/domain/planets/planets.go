@@ -0,0 +1,236 @@
+package planets
+
+import (
+	"math"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// Planet is a planet of the solar system seen from the Earth
+type Planet int
+
+const (
+	Mercury Planet = iota
+	Venus
+	Mars
+	Jupiter
+	Saturn
+	Uranus
+	Neptune
+)
+
+// Planets lists the planets from Mercury to Neptune
+var Planets = []Planet{Mercury, Venus, Mars, Jupiter, Saturn, Uranus, Neptune}
+
+// MinAltitude is the altitude in degrees above which a planet clears the murk of the horizon
+const MinAltitude = 10
+
+// horizonAltitude is the altitude in degrees of a planet at rise and set, accounting for refraction
+const horizonAltitude = -0.5667
+
+// twilightAltitude is the sun altitude in degrees below which planets stand out of the twilight
+const twilightAltitude = -6
+
+// saturnPole is the direction of the pole of Saturn's rings
+var saturnPole = unit(astro.Equatorial{RightAscension: 40.589, Declination: 83.537})
+
+// planetData holds the orbit and the size of a planet
+type planetData struct {
+	orbit    orbitalElements
+	diameter float64 // equatorial diameter in arcseconds seen from 1 AU
+}
+
+// data lists the orbital elements of the planets, in the order of the Planet constants
+var data = []planetData{
+	{orbitalElements{
+		0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081,
+	}, 6.72},
+	{orbitalElements{
+		0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418,
+	}, 16.82},
+	{orbitalElements{
+		1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343,
+	}, 9.36},
+	{orbitalElements{
+		5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106,
+	}, 196.88},
+	{orbitalElements{
+		9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794,
+	}, 165.46},
+	{orbitalElements{
+		19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589,
+	}, 70.04},
+	{orbitalElements{
+		30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664,
+	}, 67.0},
+}
+
+// String returns the identifier of the planet
+func (p Planet) String() string {
+	switch p {
+	case Mercury:
+		return "mercury"
+	case Venus:
+		return "venus"
+	case Mars:
+		return "mars"
+	case Jupiter:
+		return "jupiter"
+	case Saturn:
+		return "saturn"
+	case Uranus:
+		return "uranus"
+	default:
+		return "neptune"
+	}
+}
+
+// Visibility holds when and how well a planet can be seen during a night
+type Visibility struct {
+	Planet          Planet
+	Rise            time.Time // zero when the planet does not rise in the 24 hours around the night
+	Transit         time.Time
+	TransitAltitude float64 // altitude at transit, in degrees
+	Set             time.Time
+	MaxAltitude     float64          // highest altitude between sunset and sunrise, in degrees
+	Magnitude       float64          // apparent visual magnitude
+	Diameter        float64          // apparent equatorial diameter, in arcseconds
+	Elongation      float64          // angular distance from the sun in degrees, positive in the evening sky
+	Observable      []astro.Interval // periods above MinAltitude with the sun below -6°
+}
+
+// Clear returns the observable periods falling within the given clear windows
+func (v Visibility) Clear(windows []astro.Interval) []astro.Interval {
+	return astro.Intersect(v.Observable, windows)
+}
+
+// Position returns the equatorial coordinates of a planet seen from the Earth at t
+func Position(p Planet, t time.Time) astro.Equatorial {
+	centuries := julianCenturies(t)
+	return equatorial(data[p].orbit.position(centuries).sub(earth.position(centuries)))
+}
+
+// sunPosition returns the equatorial coordinates of the sun seen from the Earth at t
+func sunPosition(t time.Time) astro.Equatorial {
+	return equatorial(vector{}.sub(earth.position(julianCenturies(t))))
+}
+
+// GetVisibility calculates the visibility of every planet seen from lat, lon during night,
+// usually from sunset to sunrise. Rise, transit and set are searched within the 24 hours
+// around the middle of the night, with minute precision. Times are in the location of night.Start.
+func GetVisibility(lat, lon float64, night astro.Interval) []Visibility {
+	middle := night.Start.Add(night.Duration() / 2).Truncate(time.Minute)
+	day := astro.Interval{Start: middle.Add(-12 * time.Hour), End: middle.Add(12 * time.Hour)}
+
+	visibilities := make([]Visibility, len(Planets))
+	for i, planet := range Planets {
+		visibilities[i] = getPlanetVisibility(planet, lat, lon, day, night)
+		visibilities[i].Magnitude, visibilities[i].Diameter, visibilities[i].Elongation = appearance(planet, middle)
+	}
+	return visibilities
+}
+
+// getPlanetVisibility finds when a planet rises, transits and sets during day and how high
+// and how long it can be observed during night
+func getPlanetVisibility(planet Planet, lat, lon float64, day, night astro.Interval) Visibility {
+	visibility := Visibility{Planet: planet, MaxAltitude: math.Inf(-1)}
+	loc := night.Start.Location()
+
+	var previous time.Time
+	var previousAltitude, previousHourAngle float64
+	for t := day.Start; !t.After(day.End); t = t.Add(time.Minute) {
+		position := Position(planet, t)
+		altitude, _ := astro.Horizontal(position, lat, lon, t)
+		hourAngle := astro.HourAngle(position, lon, t)
+
+		if !previous.IsZero() {
+			switch {
+			case visibility.Rise.IsZero() && previousAltitude < horizonAltitude && altitude >= horizonAltitude:
+				visibility.Rise = t.In(loc)
+			case visibility.Set.IsZero() && previousAltitude >= horizonAltitude && altitude < horizonAltitude:
+				visibility.Set = t.In(loc)
+			}
+			if visibility.Transit.IsZero() && previousHourAngle < 0 && hourAngle >= 0 {
+				visibility.Transit = t.In(loc)
+				visibility.TransitAltitude = altitude
+			}
+		}
+		if !t.Before(night.Start) && !t.After(night.End) {
+			visibility.MaxAltitude = math.Max(visibility.MaxAltitude, altitude)
+		}
+		previous, previousAltitude, previousHourAngle = t, altitude, hourAngle
+	}
+
+	visibility.Observable = astro.Scan(night, func(t time.Time) bool {
+		sunAltitude, _ := astro.Horizontal(sunPosition(t), lat, lon, t)
+		altitude, _ := astro.Horizontal(Position(planet, t), lat, lon, t)
+		return sunAltitude < twilightAltitude && altitude >= MinAltitude
+	})
+	for i, interval := range visibility.Observable {
+		visibility.Observable[i] = astro.Interval{Start: interval.Start.In(loc), End: interval.End.In(loc)}
+	}
+
+	return visibility
+}
+
+// appearance returns the magnitude, apparent diameter and elongation of a planet at t
+func appearance(planet Planet, t time.Time) (magnitude, diameter, elongation float64) {
+	centuries := julianCenturies(t)
+	earthPosition := earth.position(centuries)
+	heliocentric := data[planet].orbit.position(centuries)
+	geocentric := heliocentric.sub(earthPosition)
+	sun := vector{}.sub(earthPosition)
+
+	r, delta, sunDistance := heliocentric.length(), geocentric.length(), sun.length()
+	phase := degrees(math.Acos(math.Max(-1, math.Min(1, (r*r+delta*delta-sunDistance*sunDistance)/(2*r*delta)))))
+
+	elongation = degrees(math.Acos(math.Max(-1, math.Min(1, geocentric.dot(sun)/(delta*sunDistance)))))
+	// East of the sun, the planet sets after it and shows in the evening sky
+	if normalize(degrees(math.Atan2(geocentric.y, geocentric.x)-math.Atan2(sun.y, sun.x))) < 0 {
+		elongation = -elongation
+	}
+
+	return visualMagnitude(planet, r, delta, phase, geocentric), data[planet].diameter / delta, elongation
+}
+
+// visualMagnitude returns the apparent magnitude of a planet at distance r from the sun and
+// delta from the Earth, seen under the phase angle phase in degrees, after Meeus,
+// "Astronomical Algorithms", chapter 41
+func visualMagnitude(planet Planet, r, delta, phase float64, geocentric vector) float64 {
+	distance := 5 * math.Log10(r*delta)
+	switch planet {
+	case Mercury:
+		return -0.42 + distance + 0.0380*phase - 0.000273*phase*phase + 0.000002*phase*phase*phase
+	case Venus:
+		return -4.40 + distance + 0.0009*phase + 0.000239*phase*phase - 0.00000065*phase*phase*phase
+	case Mars:
+		return -1.52 + distance + 0.016*phase
+	case Jupiter:
+		return -9.40 + distance + 0.005*phase
+	case Saturn:
+		// The rings brighten the planet as they open towards the Earth
+		tilt := math.Abs(unit(equatorial(geocentric)).dot(saturnPole))
+		return -8.88 + distance - 2.60*tilt + 1.25*tilt*tilt
+	case Uranus:
+		return -7.19 + distance
+	default:
+		return -6.87 + distance
+	}
+}
+
+// normalize brings an angle between -180 and 180 degrees
+func normalize(angle float64) float64 {
+	angle = math.Mod(angle+180, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle - 180
+}
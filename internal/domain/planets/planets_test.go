@@ -0,0 +1,112 @@
+package planets
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+func TestGreatConjunction(t *testing.T) {
+	// Jupiter and Saturn passed 0.1° apart in Capricornus on 21 December 2020
+	at := time.Date(2020, 12, 21, 18, 0, 0, 0, time.UTC)
+	jupiter, saturn := Position(Jupiter, at), Position(Saturn, at)
+
+	if separation := astro.Separation(jupiter, saturn); separation > 0.2 {
+		t.Errorf("Jupiter-Saturn separation = %.2f°, want about 0.1°", separation)
+	}
+	if math.Abs(jupiter.RightAscension-302.5) > 0.5 || math.Abs(jupiter.Declination+20.5) > 0.5 {
+		t.Errorf("Jupiter at %.2f°, %.2f°, want about 302.5°, -20.5°", jupiter.RightAscension, jupiter.Declination)
+	}
+}
+
+func TestAppearance(t *testing.T) {
+	tests := []struct {
+		name       string
+		planet     Planet
+		at         time.Time
+		magnitude  float64
+		diameter   float64
+		elongation float64 // absolute, in degrees
+	}{
+		{"Mars at the 2020 opposition", Mars, time.Date(2020, 10, 13, 23, 0, 0, 0, time.UTC), -2.6, 22.3, 177},
+		{"Venus at greatest elongation in 2023", Venus, time.Date(2023, 6, 4, 12, 0, 0, 0, time.UTC), -4.3, 23.7, 45.4},
+		{"Jupiter at the 2022 opposition", Jupiter, time.Date(2022, 9, 26, 12, 0, 0, 0, time.UTC), -2.9, 49.9, 178},
+		{"Saturn at the 2023 opposition", Saturn, time.Date(2023, 8, 27, 12, 0, 0, 0, time.UTC), 0.4, 18.9, 178},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			magnitude, diameter, elongation := appearance(tt.planet, tt.at)
+			if math.Abs(magnitude-tt.magnitude) > 0.15 {
+				t.Errorf("magnitude = %.2f, want %.1f", magnitude, tt.magnitude)
+			}
+			if math.Abs(diameter-tt.diameter) > 0.5 {
+				t.Errorf("diameter = %.1f\", want %.1f\"", diameter, tt.diameter)
+			}
+			if math.Abs(math.Abs(elongation)-tt.elongation) > 1.5 {
+				t.Errorf("elongation = %.1f°, want %.1f°", math.Abs(elongation), tt.elongation)
+			}
+		})
+	}
+
+	// Venus stood in the evening sky, east of the sun
+	if _, _, elongation := appearance(Venus, time.Date(2023, 6, 4, 12, 0, 0, 0, time.UTC)); elongation <= 0 {
+		t.Errorf("Venus elongation = %.1f°, want east of the sun (positive)", elongation)
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	// Jupiter from Greenwich on 21 December 2020, at declination -20.5°
+	night := astro.Interval{
+		Start: time.Date(2020, 12, 21, 16, 0, 0, 0, time.UTC),
+		End:   time.Date(2020, 12, 22, 8, 0, 0, 0, time.UTC),
+	}
+	jupiter := GetVisibility(51.48, 0, night)[Jupiter]
+
+	times := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"transit", jupiter.Transit, time.Date(2020, 12, 21, 14, 6, 0, 0, time.UTC)},
+		{"set", jupiter.Set, time.Date(2020, 12, 21, 18, 18, 0, 0, time.UTC)},
+		{"rise", jupiter.Rise, time.Date(2020, 12, 22, 9, 50, 0, 0, time.UTC)},
+	}
+	for _, tt := range times {
+		if diff := tt.got.Sub(tt.want).Abs(); diff > 5*time.Minute {
+			t.Errorf("%s = %s, want %s within 5 minutes", tt.name, tt.got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+		}
+	}
+	if math.Abs(jupiter.TransitAltitude-18) > 0.5 {
+		t.Errorf("transit altitude = %.1f°, want 18°", jupiter.TransitAltitude)
+	}
+
+	// It stands above 10° only in the twilight, setting before the end of the night
+	for _, interval := range jupiter.Observable {
+		if interval.End.After(jupiter.Set) {
+			t.Errorf("observable until %s, after setting at %s", interval.End, jupiter.Set)
+		}
+	}
+	if clear := jupiter.Clear([]astro.Interval{{Start: night.Start.Add(4 * time.Hour), End: night.End}}); len(clear) != 0 {
+		t.Errorf("clear periods after Jupiter set: %v", clear)
+	}
+}
+
+func TestTimesFollowTheNightLocation(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+	night := astro.Interval{
+		Start: time.Date(2020, 12, 21, 17, 0, 0, 0, paris),
+		End:   time.Date(2020, 12, 22, 9, 0, 0, 0, paris),
+	}
+
+	for _, visibility := range GetVisibility(48.85, 2.35, night) {
+		if visibility.Transit.Location() != paris {
+			t.Errorf("%s transit in %s, want Europe/Paris", visibility.Planet, visibility.Transit.Location())
+		}
+	}
+}
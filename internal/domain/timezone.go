@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Location returns the time zone of the forecast site
+func (w WeatherData) Location() *time.Location {
+	return siteLocation(w.Timezone, w.TimezoneAbbr, w.UTCOffset)
+}
+
+// Location returns the time zone of the compared forecasts
+func (c ModelComparison) Location() *time.Location {
+	return siteLocation(c.Timezone, "", c.UTCOffset)
+}
+
+// Location returns the time zone of the recorded forecast
+func (r ForecastRecord) Location() *time.Location {
+	return siteLocation(r.Timezone, "", r.UTCOffset)
+}
+
+// Location returns the time zone of the observed weather
+func (h HistoricalWeather) Location() *time.Location {
+	return siteLocation(h.Timezone, "", h.UTCOffset)
+}
+
+// siteLocation loads the IANA time zone of a site. When it is unknown, it falls back
+// to a fixed zone with the UTC offset of the forecast, which ignores daylight saving changes.
+func siteLocation(name, abbreviation string, utcOffset int) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	if abbreviation == "" && utcOffset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone(abbreviation, utcOffset)
+}
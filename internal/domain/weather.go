@@ -12,8 +12,13 @@ type WeatherData struct {
 	Longitude      float64        `json:"longitude"`
 	Elevation      float64        `json:"elevation"`
 	GenerationTime float64        `json:"generationtime_ms"`
+	UTCOffset      int            `json:"utc_offset_seconds"`
 	Timezone       string         `json:"timezone"`
 	TimezoneAbbr   string         `json:"timezone_abbreviation"`
+
+	// AirQuality and Ensemble are fetched separately and are nil when unavailable
+	AirQuality *AirQualityData `json:"air_quality,omitempty"`
+	Ensemble   *EnsembleData   `json:"ensemble,omitempty"`
 }
 
 type CurrentWeather struct {
@@ -47,8 +52,28 @@ type HourlyWeather struct {
 	CloudCoverHigh           []int     `json:"cloud_cover_high"`
 	WindSpeed                []float64 `json:"wind_speed_10m"`
 	WindDirection            []float64 `json:"wind_direction_10m"`
+	WindGusts                []float64 `json:"wind_gusts_10m"`
 	PrecipitationProbability []int     `json:"precipitation_probability"`
 	DewPoint                 []float64 `json:"dew_point_2m"`
+
+	// Visibility is nil where the model has no value
+	Visibility []*float64 `json:"visibility"`
+
+	// Upper air, on pressure levels, nil where the model has no value
+	WindSpeed850hPa   []*float64 `json:"wind_speed_850hPa"`
+	WindSpeed700hPa   []*float64 `json:"wind_speed_700hPa"`
+	WindSpeed500hPa   []*float64 `json:"wind_speed_500hPa"`
+	WindSpeed300hPa   []*float64 `json:"wind_speed_300hPa"`
+	WindSpeed250hPa   []*float64 `json:"wind_speed_250hPa"`
+	WindSpeed200hPa   []*float64 `json:"wind_speed_200hPa"`
+	Temperature850hPa []*float64 `json:"temperature_850hPa"`
+	Temperature700hPa []*float64 `json:"temperature_700hPa"`
+	Temperature500hPa []*float64 `json:"temperature_500hPa"`
+	Temperature300hPa []*float64 `json:"temperature_300hPa"`
+	Temperature250hPa []*float64 `json:"temperature_250hPa"`
+	Temperature200hPa []*float64 `json:"temperature_200hPa"`
+	Humidity700hPa    []*float64 `json:"relative_humidity_700hPa"`
+	Humidity500hPa    []*float64 `json:"relative_humidity_500hPa"`
 }
 
 type HourlyUnits struct {
@@ -60,8 +85,10 @@ type HourlyUnits struct {
 	CloudCoverHigh           string `json:"cloud_cover_high"`
 	WindSpeed                string `json:"wind_speed_10m"`
 	WindDirection            string `json:"wind_direction_10m"`
+	WindGusts                string `json:"wind_gusts_10m"`
 	PrecipitationProbability string `json:"precipitation_probability"`
 	DewPoint                 string `json:"dew_point_2m"`
+	Visibility               string `json:"visibility"`
 }
 
 type DailyWeather struct {
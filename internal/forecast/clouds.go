@@ -0,0 +1,33 @@
+package forecast
+
+import "math"
+
+// CloudLayerWeights weigh how much each cloud layer spoils the sky, from 0 (harmless) to 1 (opaque)
+type CloudLayerWeights struct {
+	Low  float64 // stratus and cumulus, below about 2 km
+	Mid  float64 // altostratus and altocumulus
+	High float64 // cirrus, often thin enough to see through
+}
+
+// calculateEffectiveCloudCover weighs the cloud layers of an hour and scales the total cloud
+// cover accordingly, so solid low clouds count fully while thin cirrus counts partly.
+// Layers are assumed to overlap randomly. Without layer data the total is returned as is.
+func calculateEffectiveCloudCover(weights CloudLayerWeights, clouds, low, mid, high int) int {
+	layers := []struct {
+		cover  int
+		weight float64
+	}{{low, weights.Low}, {mid, weights.Mid}, {high, weights.High}}
+
+	clear, weightedClear := 1.0, 1.0
+	for _, layer := range layers {
+		cover := math.Max(0, math.Min(100, float64(layer.cover))) / 100
+		clear *= 1 - cover
+		weightedClear *= 1 - layer.weight*cover
+	}
+	if clear >= 1 {
+		return clouds
+	}
+
+	effective := float64(clouds) * (1 - weightedClear) / (1 - clear)
+	return int(math.Round(math.Max(0, math.Min(100, effective))))
+}
@@ -0,0 +1,50 @@
+package forecast
+
+import "testing"
+
+func TestCalculateEffectiveCloudCover(t *testing.T) {
+	weights := CloudLayerWeights{Low: 1, Mid: 0.8, High: 0.5}
+
+	tests := []struct {
+		name           string
+		weights        CloudLayerWeights
+		clouds         int
+		low, mid, high int
+		want           int
+	}{
+		{name: "without layers", weights: weights, clouds: 40, want: 40},
+		{name: "low clouds", weights: weights, clouds: 60, low: 60, want: 60},
+		{name: "mid clouds", weights: weights, clouds: 50, mid: 50, want: 40},
+		{name: "thin cirrus", weights: weights, clouds: 60, high: 60, want: 30},
+		{name: "overcast cirrus", weights: weights, clouds: 100, high: 100, want: 50},
+		{name: "low under overcast cirrus", weights: weights, clouds: 100, low: 50, high: 100, want: 75},
+		{name: "out of range layer", weights: weights, clouds: 100, low: -10, high: 150, want: 50},
+		{name: "harmless layers", weights: CloudLayerWeights{}, clouds: 80, low: 30, high: 80, want: 0},
+		{name: "opaque layers", weights: CloudLayerWeights{Low: 1, Mid: 1, High: 1}, clouds: 70, low: 40, mid: 30, high: 20, want: 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateEffectiveCloudCover(tt.weights, tt.clouds, tt.low, tt.mid, tt.high)
+			if got != tt.want {
+				t.Errorf("calculateEffectiveCloudCover = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveCloudCoverFavorsHighClouds(t *testing.T) {
+	for _, profile := range Profiles() {
+		t.Run(profile.Name, func(t *testing.T) {
+			low := calculateEffectiveCloudCover(profile.Clouds, 50, 50, 0, 0)
+			mid := calculateEffectiveCloudCover(profile.Clouds, 50, 0, 50, 0)
+			high := calculateEffectiveCloudCover(profile.Clouds, 50, 0, 0, 50)
+			if low != 50 {
+				t.Errorf("low clouds = %d, want 50", low)
+			}
+			if !(high <= mid && mid <= low) {
+				t.Errorf("high, mid, low clouds = %d, %d, %d, want increasing", high, mid, low)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// DewRisk is the likelihood of dew forming on exposed optics
+type DewRisk int
+
+const (
+	DewRiskNone DewRisk = iota
+	DewRiskLow
+	DewRiskModerate
+	DewRiskHigh
+)
+
+// maxRadiativeCooling is how far below the air temperature optics cool under a clear, calm sky, in °C
+const maxRadiativeCooling = 3.0
+
+// String returns the identifier of the risk level
+func (r DewRisk) String() string {
+	switch r {
+	case DewRiskLow:
+		return "low"
+	case DewRiskModerate:
+		return "moderate"
+	case DewRiskHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// calculateDewRisk estimates the dew risk of an hour and the suggested dew heater power in percent.
+// Optics radiate heat to a clear sky and end up colder than the air unless wind mixes it,
+// so the spread that matters is between the dew point and the optics temperature.
+func calculateDewRisk(temperature, dewPoint float64, humidity, clouds int, windSpeed float64) (DewRisk, int) {
+	clearFactor := 1 - float64(clouds)/100
+	calmFactor := math.Max(0, 1-windSpeed/20)
+	opticsTemperature := temperature - maxRadiativeCooling*clearFactor*calmFactor
+	spread := opticsTemperature - dewPoint
+
+	var risk DewRisk
+	switch {
+	case spread <= 0:
+		risk = DewRiskHigh
+	case spread <= 2:
+		risk = DewRiskModerate
+	case spread <= 4:
+		risk = DewRiskLow
+	}
+	if humidity >= 95 && risk < DewRiskModerate {
+		risk = DewRiskModerate
+	}
+
+	power := math.Max(0, math.Min(100, (5-spread)/5*100))
+	return risk, int(math.Round(power/10) * 10)
+}
+
+// dewSummary returns the highest dew risk of the night, when it first reaches a moderate level
+// and the highest suggested dew heater power
+func dewSummary(nightForecastData []ForecastHour) (DewRisk, time.Time, int) {
+	var risk DewRisk
+	var start time.Time
+	power := 0
+
+	for _, hour := range nightForecastData {
+		if hour.DewRisk > risk {
+			risk = hour.DewRisk
+		}
+		if hour.DewRisk >= DewRiskModerate && start.IsZero() {
+			start = hour.DateTime
+		}
+		if hour.DewHeaterPower > power {
+			power = hour.DewHeaterPower
+		}
+	}
+
+	return risk, start, power
+}
@@ -0,0 +1,80 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateDewRisk(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature float64
+		dewPoint    float64
+		humidity    int
+		clouds      int
+		windSpeed   float64
+		wantRisk    DewRisk
+		wantPower   int
+	}{
+		{"dry air", 15, 2, 40, 0, 5, DewRiskNone, 0},
+		{"clear and calm", 10, 8, 85, 0, 0, DewRiskHigh, 100},
+		{"overcast", 10, 8, 85, 100, 0, DewRiskModerate, 60},
+		{"clear and windy", 10, 8, 85, 0, 20, DewRiskModerate, 60},
+		{"half clouded breeze", 10, 6, 80, 50, 10, DewRiskLow, 40},
+		{"low spread limit", 12, 8, 70, 100, 0, DewRiskLow, 20},
+		{"above the low spread", 12.5, 8, 70, 100, 0, DewRiskNone, 10},
+		{"saturated air", 12, 2, 96, 100, 10, DewRiskModerate, 0},
+		{"saturated and cold optics", 10, 9, 98, 0, 0, DewRiskHigh, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk, power := calculateDewRisk(tt.temperature, tt.dewPoint, tt.humidity, tt.clouds, tt.windSpeed)
+			if risk != tt.wantRisk {
+				t.Errorf("risk = %v, want %v", risk, tt.wantRisk)
+			}
+			if power != tt.wantPower {
+				t.Errorf("power = %d, want %d", power, tt.wantPower)
+			}
+		})
+	}
+}
+
+func TestDewSummary(t *testing.T) {
+	start := time.Date(2024, time.March, 10, 21, 0, 0, 0, time.UTC)
+	hour := func(offset int, risk DewRisk, power int) ForecastHour {
+		return ForecastHour{DateTime: start.Add(time.Duration(offset) * time.Hour), DewRisk: risk, DewHeaterPower: power}
+	}
+
+	tests := []struct {
+		name      string
+		hours     []ForecastHour
+		wantRisk  DewRisk
+		wantStart time.Time
+		wantPower int
+	}{
+		{name: "no hours", wantRisk: DewRiskNone},
+		{
+			name:      "dry night",
+			hours:     []ForecastHour{hour(0, DewRiskNone, 0), hour(1, DewRiskLow, 20)},
+			wantRisk:  DewRiskLow,
+			wantPower: 20,
+		},
+		{
+			name:      "dew after midnight",
+			hours:     []ForecastHour{hour(0, DewRiskLow, 20), hour(3, DewRiskModerate, 60), hour(4, DewRiskHigh, 50), hour(5, DewRiskModerate, 40)},
+			wantRisk:  DewRiskHigh,
+			wantStart: start.Add(3 * time.Hour),
+			wantPower: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risk, dewStart, power := dewSummary(tt.hours)
+			if risk != tt.wantRisk || !dewStart.Equal(tt.wantStart) || power != tt.wantPower {
+				t.Errorf("dewSummary = %v, %v, %d, want %v, %v, %d", risk, dewStart, power, tt.wantRisk, tt.wantStart, tt.wantPower)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package forecast
+
+import (
+	"math"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+// unknownProbability marks a clear-sky probability or confidence without ensemble data
+const unknownProbability = -1
+
+// ensembleByTime returns the cloud cover of every ensemble member indexed by time string.
+// Members without a forecast for an hour have a negative value. It returns nil when there is no ensemble data.
+func ensembleByTime(ensemble *domain.EnsembleData) map[string][]float64 {
+	if ensemble == nil {
+		return nil
+	}
+
+	hours := make(map[string][]float64, len(ensemble.Time))
+	for i, t := range ensemble.Time {
+		members := make([]float64, len(ensemble.CloudCover))
+		for m, series := range ensemble.CloudCover {
+			members[m] = -1
+			if i < len(series) {
+				members[m] = series[i]
+			}
+		}
+		hours[t] = members
+	}
+	return hours
+}
+
+// calculateClearProbability returns the percentage of ensemble members forecasting a cloud
+// cover at or below the threshold, or unknownProbability when no member has a forecast
+func calculateClearProbability(members []float64, threshold int) int {
+	var clear, total int
+	for _, clouds := range members {
+		if clouds < 0 {
+			continue
+		}
+		total++
+		if clouds <= float64(threshold) {
+			clear++
+		}
+	}
+	if total == 0 {
+		return unknownProbability
+	}
+	return int(math.Round(100 * float64(clear) / float64(total)))
+}
+
+// calculateWindowConfidence returns the percentage of ensemble members whose average cloud
+// cover over the hours stays at or below the threshold, that is the probability that the
+// window actually happens. Only members with a forecast for every hour are counted.
+func calculateWindowConfidence(hours []ForecastHour, threshold int) int {
+	if len(hours) == 0 || len(hours[0].EnsembleCloudCover) == 0 {
+		return unknownProbability
+	}
+
+	var clear, total int
+	for m := range hours[0].EnsembleCloudCover {
+		sum, complete := 0.0, true
+		for _, hour := range hours {
+			if m >= len(hour.EnsembleCloudCover) || hour.EnsembleCloudCover[m] < 0 {
+				complete = false
+				break
+			}
+			sum += hour.EnsembleCloudCover[m]
+		}
+		if !complete {
+			continue
+		}
+		total++
+		if sum/float64(len(hours)) <= float64(threshold) {
+			clear++
+		}
+	}
+	if total == 0 {
+		return unknownProbability
+	}
+	return int(math.Round(100 * float64(clear) / float64(total)))
+}
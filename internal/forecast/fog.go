@@ -0,0 +1,44 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// highFogProbability is the fog probability in percent from which an hour is left out of
+// the observation windows and fog is announced in the night summary
+const highFogProbability = 50
+
+// calculateFogProbability estimates the probability in percent of fog forming during an hour.
+// Radiation fog needs saturated air (humidity near 100%, dew point close to the temperature),
+// calm wind and a clear sky letting the ground cool. A forecast visibility under a few kilometres
+// raises the probability, a negative visibility means it is unknown.
+func calculateFogProbability(temperature, dewPoint float64, humidity, clouds int, windSpeed, visibility float64) int {
+	humidityFactor := math.Max(0, math.Min(1, (float64(humidity)-90)/10))
+	spreadFactor := math.Max(0, math.Min(1, 1-math.Abs(temperature-dewPoint)/3))
+	calmFactor := math.Max(0, math.Min(1, (20-windSpeed)/15))
+	clearFactor := 1 - math.Max(0, math.Min(100, float64(clouds)))/100
+
+	probability := math.Min(humidityFactor, spreadFactor) * calmFactor * (0.4 + 0.6*clearFactor)
+	if visibility >= 0 {
+		probability = math.Max(probability, math.Max(0, math.Min(1, (5000-visibility)/4000)))
+	}
+
+	return int(math.Round(100 * probability))
+}
+
+// fogSummary returns the first hour of the night with a high fog probability, if any,
+// and the highest fog probability of the night
+func fogSummary(nightForecastData []ForecastHour) (time.Time, int) {
+	var start time.Time
+	probability := 0
+
+	for _, hour := range nightForecastData {
+		if hour.FogProbability >= highFogProbability && start.IsZero() {
+			start = hour.DateTime
+		}
+		probability = max(probability, hour.FogProbability)
+	}
+
+	return start, probability
+}
@@ -0,0 +1,101 @@
+package forecast
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+func TestCalculateFogProbability(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature float64
+		dewPoint    float64
+		humidity    int
+		clouds      int
+		windSpeed   float64
+		visibility  float64
+		want        int
+	}{
+		{"dry air", 15, 5, 60, 0, 5, -1, 0},
+		{"saturated, calm and clear", 8, 8, 100, 0, 0, -1, 100},
+		{"saturated under overcast", 8, 8, 100, 100, 0, -1, 40},
+		{"saturated and windy", 8, 8, 100, 0, 20, -1, 0},
+		{"saturated in a breeze", 8, 8, 100, 0, 12.5, -1, 50},
+		{"humidity 95%", 8, 8, 95, 0, 0, -1, 50},
+		{"dew point spread", 9.5, 8, 100, 0, 0, -1, 50},
+		{"low visibility in dry air", 15, 5, 60, 0, 5, 1000, 100},
+		{"reduced visibility", 15, 5, 60, 0, 5, 3000, 50},
+		{"good visibility", 15, 5, 60, 0, 5, 10000, 0},
+		{"good visibility in saturated air", 8, 8, 100, 0, 0, 20000, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateFogProbability(tt.temperature, tt.dewPoint, tt.humidity, tt.clouds, tt.windSpeed, tt.visibility)
+			if got != tt.want {
+				t.Errorf("calculateFogProbability = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullVisibilityIsUnknown(t *testing.T) {
+	var data domain.WeatherData
+	response := `{"latitude": 45, "longitude": 5, "timezone": "UTC", "hourly": {
+		"time": ["2024-11-05T22:00", "2024-11-05T23:00"],
+		"temperature_2m": [15, 15],
+		"dew_point_2m": [5, 5],
+		"relative_humidity_2m": [60, 60],
+		"wind_speed_10m": [5, 5],
+		"visibility": [null, 800]
+	}}`
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	hours := GenerateForecastData(data)
+	if hours[0].Visibility != -1 {
+		t.Errorf("null visibility = %v, want -1", hours[0].Visibility)
+	}
+	if hours[0].FogProbability != 0 {
+		t.Errorf("fog probability with a null visibility = %d, want 0", hours[0].FogProbability)
+	}
+	if hours[1].Visibility != 800 || hours[1].FogProbability != 100 {
+		t.Errorf("800 m visibility gives %v m and %d%% fog, want 800 m and 100%%", hours[1].Visibility, hours[1].FogProbability)
+	}
+}
+
+func TestFogSummary(t *testing.T) {
+	start := time.Date(2024, time.November, 5, 20, 0, 0, 0, time.UTC)
+	hour := func(offset, probability int) ForecastHour {
+		return ForecastHour{DateTime: start.Add(time.Duration(offset) * time.Hour), FogProbability: probability}
+	}
+
+	tests := []struct {
+		name            string
+		hours           []ForecastHour
+		wantStart       time.Time
+		wantProbability int
+	}{
+		{name: "no hours"},
+		{name: "no fog", hours: []ForecastHour{hour(0, 10), hour(1, highFogProbability-1)}, wantProbability: highFogProbability - 1},
+		{
+			name:            "fog before dawn",
+			hours:           []ForecastHour{hour(0, 20), hour(6, highFogProbability), hour(7, 80), hour(8, 30)},
+			wantStart:       start.Add(6 * time.Hour),
+			wantProbability: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fogStart, probability := fogSummary(tt.hours)
+			if !fogStart.Equal(tt.wantStart) || probability != tt.wantProbability {
+				t.Errorf("fogSummary = %v, %d, want %v, %d", fogStart, probability, tt.wantStart, tt.wantProbability)
+			}
+		})
+	}
+}
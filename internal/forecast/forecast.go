@@ -5,9 +5,41 @@ import (
 	"time"
 
 	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
 	"driffaud.fr/odin/internal/util"
 )
 
+const (
+	// DefaultCloudCoverThreshold is the highest cloud cover percentage considered good for observation
+	DefaultCloudCoverThreshold = 30
+	// DefaultConsecutiveGoodHours is the minimum length in hours of an observation window
+	DefaultConsecutiveGoodHours = 2
+	// DefaultMaxMoonAltitude is the highest moon altitude in degrees for the sky to count as dark
+	DefaultMaxMoonAltitude = 0
+	// DefaultMinTargetAltitude is the lowest altitude in degrees at which deep-sky targets are suggested
+	DefaultMinTargetAltitude = 30
+)
+
+// Thresholds define the conditions used to find observation windows
+type Thresholds struct {
+	CloudCover        int           // highest cloud cover percentage for an hour to count as clear
+	MinWindowHours    int           // minimum number of consecutive clear hours in a window
+	MaxMoonAltitude   float64       // highest moon altitude in degrees for an hour to count as dark
+	WindTolerance     WindTolerance // hours with a high shake risk for this tolerance are never clear
+	MinTargetAltitude float64       // lowest altitude in degrees of the suggested deep-sky targets
+}
+
+// DefaultThresholds returns the thresholds used when none are configured
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		CloudCover:        DefaultCloudCoverThreshold,
+		MinWindowHours:    DefaultConsecutiveGoodHours,
+		MaxMoonAltitude:   DefaultMaxMoonAltitude,
+		WindTolerance:     DefaultWindTolerance,
+		MinTargetAltitude: DefaultMinTargetAltitude,
+	}
+}
+
 // ForecastHour represents a single hour of forecast data
 type ForecastHour struct {
 	DateTime                 time.Time
@@ -16,31 +48,34 @@ type ForecastHour struct {
 	CloudsLow                int
 	CloudsMid                int
 	CloudsHigh               int
+	EffectiveClouds          int // cloud cover weighted by layer with the profile's cloud layer weights
 	Temperature              float64
 	WindSpeed                float64
 	WindDirection            float64
+	WindGusts                float64 // strongest gusts in km/h
+	ShakeRisk                ShakeRisk
 	Humidity                 int
 	DewPoint                 float64
 	PrecipitationProbability int
+	Visibility               float64 // in metres, -1 when unknown
+	FogProbability           int     // percent
 	Rating                   int
 	Seeing                   int
-}
-
-// BestObservationInfo represents the best time range for astronomical observation
-type BestObservationInfo struct {
-	TimeRange        *TimeRange
-	LowestCloudCover int
-}
-
-// TimeRange represents a start and end hour for observation
-type TimeRange struct {
-	Start int
-	End   int
+	SeeingArcsec             float64   // estimated from the upper-air winds, 0 when unavailable
+	JetStreamWind            float64   // strongest wind above 9 km in km/h
+	Transparency             int       // 1 to 5, 0 when unknown
+	OpticalDepth             float64   // estimated from the aerosols and the humidity aloft
+	EnsembleCloudCover       []float64 // cloud cover of every ensemble member, nil when unknown
+	ClearProbability         int       // percent of ensemble members under the cloud cover threshold, -1 when unknown
+	DewRisk                  DewRisk
+	DewHeaterPower           int // suggested dew heater power in percent
+	Moon                     astro.MoonPosition
 }
 
 // NightForecast contains forecast and analysis for an astronomical night
 type NightForecast struct {
-	BestObservation      BestObservationInfo
+	DarkWindows          []astro.Interval
+	Windows              []ObservationWindow // ranked from best to worst
 	ExtremeCloudCover    int
 	DisplayCloudCover    int
 	NightlyTemperature   int
@@ -50,23 +85,60 @@ type NightForecast struct {
 	MaxPrecipProbability int
 	NightlyWindDirection int
 	WindDirectionText    string
+	MaxWindGusts         int // strongest gusts of the night in km/h
+	ShakeRisk            ShakeRisk
 	SeeingIndex          int
+	TransparencyIndex    int // 0 when unknown
+	Rating               int
+	DewRisk              DewRisk
+	DewStart             time.Time // first hour with a moderate dew risk, zero if none
+	DewHeaterPower       int
+	FogStart             time.Time // first hour with a high fog probability, zero if none
+	MaxFogProbability    int
+}
+
+// BestWindow returns the best observation window of the night, if any
+func (n NightForecast) BestWindow() (ObservationWindow, bool) {
+	if len(n.Windows) == 0 {
+		return ObservationWindow{}, false
+	}
+	return n.Windows[0], true
+}
+
+// ClearIntervals returns the spans of the observation windows of the night
+func (n NightForecast) ClearIntervals() []astro.Interval {
+	intervals := make([]astro.Interval, len(n.Windows))
+	for i, window := range n.Windows {
+		intervals[i] = astro.Interval{Start: window.Start, End: window.End}
+	}
+	return intervals
 }
 
 // GenerateForecastData converts Open-Meteo weather data into a slice of hourly forecast data
 func GenerateForecastData(data domain.WeatherData) []ForecastHour {
+	return GenerateForecastDataWithProfile(data, DefaultProfile())
+}
+
+// GenerateForecastDataWithProfile converts Open-Meteo weather data into hourly forecast data,
+// rating every hour with the weights of the given profile
+func GenerateForecastDataWithProfile(data domain.WeatherData, profile Profile) []ForecastHour {
 	if len(data.Hourly.Time) == 0 {
 		return []ForecastHour{}
 	}
 
 	forecast := make([]ForecastHour, len(data.Hourly.Time))
+	airQuality := airQualityByTime(data.AirQuality)
+	ensemble := ensembleByTime(data.Ensemble)
+	// Hourly times are wall clock times of the site
+	dateTimes := util.ParseHourlyTimes(data.Hourly.Time, data.Location())
 
 	for i, timeStr := range data.Hourly.Time {
-		dateTime, _ := time.Parse(util.ISO8601Format, timeStr)
+		dateTime := dateTimes[i]
 
 		var clouds, cloudsLow, cloudsMid, cloudsHigh int
-		var temp, windSpeed, windDir, dewPoint float64
+		var temp, windSpeed, windDir, windGusts, dewPoint float64
 		var humidity, precipProb int
+		visibility := -1.0
 
 		if i < len(data.Hourly.CloudCover) {
 			clouds = data.Hourly.CloudCover[i]
@@ -89,6 +161,9 @@ func GenerateForecastData(data domain.WeatherData) []ForecastHour {
 		if i < len(data.Hourly.WindDirection) {
 			windDir = data.Hourly.WindDirection[i]
 		}
+		if i < len(data.Hourly.WindGusts) {
+			windGusts = data.Hourly.WindGusts[i]
+		}
 		if i < len(data.Hourly.RelativeHumidity) {
 			humidity = data.Hourly.RelativeHumidity[i]
 		}
@@ -98,9 +173,29 @@ func GenerateForecastData(data domain.WeatherData) []ForecastHour {
 		if i < len(data.Hourly.PrecipitationProbability) {
 			precipProb = data.Hourly.PrecipitationProbability[i]
 		}
+		if i < len(data.Hourly.Visibility) && data.Hourly.Visibility[i] != nil {
+			visibility = *data.Hourly.Visibility[i]
+		}
+
+		levels := upperAirLevels(data.Hourly, i)
+		seeingArcsec, hasUpperAir := calculateUpperAirSeeing(levels, windSpeed)
+		var seeingIndex int
+		if hasUpperAir {
+			seeingIndex = seeingIndexFromArcsec(seeingArcsec)
+		} else {
+			seeingIndex = calculateSeeingIndex(profile.Seeing, temp, dewPoint, windSpeed, humidity)
+		}
+		var aerosols *airQualityHour
+		if hour, ok := airQuality[timeStr]; ok {
+			aerosols = &hour
+		}
+		humidityAloft, hasHumidityAloft := humidityAloft(data.Hourly, i)
+		transparency, opticalDepth := calculateTransparency(aerosols, humidityAloft, hasHumidityAloft)
 
-		seeingIndex := calculateSeeingIndex(temp, dewPoint, windSpeed, humidity)
-		ratingIndex := calculateSkyQualityIndex(clouds, humidity, windSpeed, temp, dewPoint, seeingIndex)
+		effectiveClouds := calculateEffectiveCloudCover(profile.Clouds, clouds, cloudsLow, cloudsMid, cloudsHigh)
+		moon := astro.GetMoonPositionAt(data.Latitude, data.Longitude, dateTime)
+		ratingIndex := calculateSkyQualityIndex(profile.Quality, effectiveClouds, humidity, windSpeed, temp, dewPoint, seeingIndex, transparency, moon)
+		dewRisk, dewHeaterPower := calculateDewRisk(temp, dewPoint, humidity, clouds, windSpeed)
 
 		forecast[i] = ForecastHour{
 			DateTime:                 dateTime,
@@ -109,36 +204,65 @@ func GenerateForecastData(data domain.WeatherData) []ForecastHour {
 			CloudsLow:                cloudsLow,
 			CloudsMid:                cloudsMid,
 			CloudsHigh:               cloudsHigh,
+			EffectiveClouds:          effectiveClouds,
 			Temperature:              temp,
 			WindSpeed:                windSpeed,
 			WindDirection:            windDir,
+			WindGusts:                windGusts,
+			ShakeRisk:                calculateShakeRisk(profile.Thresholds.WindTolerance, windGusts, windSpeed),
 			Humidity:                 humidity,
 			DewPoint:                 dewPoint,
 			PrecipitationProbability: precipProb,
+			Visibility:               visibility,
+			FogProbability:           calculateFogProbability(temp, dewPoint, humidity, clouds, windSpeed, visibility),
 			Rating:                   ratingIndex,
 			Seeing:                   seeingIndex,
+			SeeingArcsec:             seeingArcsec,
+			JetStreamWind:            jetStreamSpeed(levels),
+			Transparency:             transparency,
+			OpticalDepth:             opticalDepth,
+			EnsembleCloudCover:       ensemble[timeStr],
+			ClearProbability:         calculateClearProbability(ensemble[timeStr], profile.Thresholds.CloudCover),
+			DewRisk:                  dewRisk,
+			DewHeaterPower:           dewHeaterPower,
+			Moon:                     moon,
 		}
 	}
 
 	return forecast
 }
 
-// AnalyzeNightForecast generates a complete night forecast analysis for astronomical observation
+// AnalyzeNightForecast generates a complete night forecast analysis for astronomical observation,
+// treating the whole night between sunset and sunrise as dark
 func AnalyzeNightForecast(forecastData []ForecastHour, sunsetTime, sunriseTime time.Time) NightForecast {
-	const goodCloudCoverThreshold = 30
-	const consecutiveGoodHoursRequired = 2
+	darkWindows := []astro.Interval{{Start: sunsetTime, End: sunriseTime}}
+	return analyzeNight(forecastData, sunsetTime, sunriseTime, darkWindows, DefaultThresholds())
+}
+
+// AnalyzeNight generates a night forecast analysis for the night described by sunInfo.
+// Observation windows are only searched within the dark windows, when the sun is below
+// -18° and the moon below the thresholds' altitude. At high latitudes, where the sun does
+// not go that low, the darkest twilight band is used instead.
+func AnalyzeNight(forecastData []ForecastHour, lat, lon float64, sunInfo astro.SunInfo, thresholds Thresholds) NightForecast {
+	darkWindows := astro.DarkWindows(lat, lon, sunInfo, thresholds.MaxMoonAltitude)
+	night := sunInfo.Night()
+	return analyzeNight(forecastData, night.Start, night.End, darkWindows, thresholds)
+}
 
+// CoversNight reports whether the forecast has hours during the night described by sunInfo
+func CoversNight(forecastData []ForecastHour, sunInfo astro.SunInfo) bool {
+	night := sunInfo.Night()
+	return len(filterNightForecastData(forecastData, night.Start, night.End)) > 0
+}
+
+func analyzeNight(forecastData []ForecastHour, sunsetTime, sunriseTime time.Time, darkWindows []astro.Interval, thresholds Thresholds) NightForecast {
 	nightForecastData := filterNightForecastData(forecastData, sunsetTime, sunriseTime)
 
-	bestObservationInfo := getBestObservationTimeRange(
-		nightForecastData,
-		goodCloudCoverThreshold,
-		consecutiveGoodHoursRequired,
-	)
+	windows := FindObservationWindows(filterDarkForecastData(nightForecastData, darkWindows), thresholds)
 	extremeCloudCover := calculateExtremeCloudCover(nightForecastData)
 	displayCloudCover := extremeCloudCover
-	if bestObservationInfo.TimeRange != nil {
-		displayCloudCover = bestObservationInfo.LowestCloudCover
+	if len(windows) > 0 {
+		displayCloudCover = int(math.Round(windows[0].CloudCover))
 	}
 	nightlyTemperature := int(math.Floor(calculateNightlyAverage(nightForecastData, "temperature")))
 	nightlyHumidity := int(math.Floor(calculateNightlyAverage(nightForecastData, "humidity")))
@@ -146,11 +270,17 @@ func AnalyzeNightForecast(forecastData []ForecastHour, sunsetTime, sunriseTime t
 	nightlyDewPoint := int(math.Floor(calculateNightlyAverage(nightForecastData, "dewPoint")))
 	maxPrecipProbability := maxPrecipitationProbability(nightForecastData)
 	seeingIndex := generateSeeingIndexForNight(nightForecastData)
+	transparencyIndex := generateTransparencyIndexForNight(nightForecastData)
+	rating := calculateNightRating(nightForecastData)
+	dewRisk, dewStart, dewHeaterPower := dewSummary(nightForecastData)
+	maxWindGusts, shakeRisk := gustSummary(nightForecastData)
+	fogStart, maxFogProbability := fogSummary(nightForecastData)
 	nightlyWindDirection := calculateWindDirectionAverage(nightForecastData)
 	windDirectionText := convertWindDirectionToNSEW(nightlyWindDirection)
 
 	return NightForecast{
-		BestObservation:      bestObservationInfo,
+		DarkWindows:          darkWindows,
+		Windows:              windows,
 		ExtremeCloudCover:    extremeCloudCover,
 		DisplayCloudCover:    displayCloudCover,
 		NightlyTemperature:   nightlyTemperature,
@@ -160,7 +290,16 @@ func AnalyzeNightForecast(forecastData []ForecastHour, sunsetTime, sunriseTime t
 		MaxPrecipProbability: maxPrecipProbability,
 		NightlyWindDirection: nightlyWindDirection,
 		WindDirectionText:    windDirectionText,
+		MaxWindGusts:         int(math.Round(maxWindGusts)),
+		ShakeRisk:            shakeRisk,
 		SeeingIndex:          seeingIndex,
+		TransparencyIndex:    transparencyIndex,
+		Rating:               rating,
+		DewRisk:              dewRisk,
+		DewStart:             dewStart,
+		DewHeaterPower:       dewHeaterPower,
+		FogStart:             fogStart,
+		MaxFogProbability:    maxFogProbability,
 	}
 }
 
@@ -178,59 +317,21 @@ func filterNightForecastData(forecastData []ForecastHour, sunsetTime, sunriseTim
 	return nightForecast
 }
 
-// getBestObservationTimeRange finds the best observation time range within tonight's astronomical night
-func getBestObservationTimeRange(data []ForecastHour, goodCloudCoverThreshold int, consecutiveGoodHoursRequired int) BestObservationInfo {
-	var observationStartTime *int
-	var observationEndTime *int
-	consecutiveGoodHours := 0
-	lowestCloudCover := math.MaxInt32
-	var extendedEndTime *int
-
-	for i := range data {
-		hourlyData := data[i]
-		hour := hourlyData.Hour
-		clouds := hourlyData.Clouds
-
-		if clouds <= goodCloudCoverThreshold {
-			if consecutiveGoodHours == 0 {
-				observationStartTime = &hour
-			}
-			consecutiveGoodHours++
-			if clouds < lowestCloudCover {
-				lowestCloudCover = clouds
-			}
-			extendedEndTime = &hour
-
-			if consecutiveGoodHours >= consecutiveGoodHoursRequired {
-				observationEndTime = extendedEndTime
-			}
-		} else {
-			if observationEndTime != nil {
-				break // Stop extending if minimum is met and conditions change
-			}
-			consecutiveGoodHours = 0
-			observationStartTime = nil
-			observationEndTime = nil
-			lowestCloudCover = math.MaxInt32
-		}
-	}
+// filterDarkForecastData keeps the hours that are dark for at least half of their duration
+func filterDarkForecastData(forecastData []ForecastHour, darkWindows []astro.Interval) []ForecastHour {
+	var darkForecast []ForecastHour
 
-	var timeRange *TimeRange
-	if observationStartTime != nil && observationEndTime != nil {
-		timeRange = &TimeRange{
-			Start: *observationStartTime,
-			End:   *observationEndTime,
+	for _, hour := range forecastData {
+		var dark time.Duration
+		for _, window := range darkWindows {
+			dark += window.Overlap(hour.DateTime, hour.DateTime.Add(time.Hour))
+		}
+		if dark >= 30*time.Minute {
+			darkForecast = append(darkForecast, hour)
 		}
 	}
 
-	if lowestCloudCover == math.MaxInt32 {
-		lowestCloudCover = 0
-	}
-
-	return BestObservationInfo{
-		TimeRange:        timeRange,
-		LowestCloudCover: lowestCloudCover,
-	}
+	return darkForecast
 }
 
 // calculateNightlyAverage calculates average for a specific meteorological parameter
@@ -297,27 +398,26 @@ func calculateExtremeCloudCover(data []ForecastHour) int {
 }
 
 // calculateSeeingIndex calculates seeing conditions for astronomical observation
-func calculateSeeingIndex(temperature, dewPoint, windSpeed float64, humidity int) int {
-	tempWeight := 0.25
-	windWeight := 0.4
-	humidityWeight := 0.15
-	dewPointWeight := 0.2
-
+func calculateSeeingIndex(weights SeeingWeights, temperature, dewPoint, windSpeed float64, humidity int) int {
 	tempDiff := math.Abs(temperature - dewPoint)
 	tempFactor := math.Max(0.1, math.Min(1, (15-tempDiff)/15))
 	windFactor := math.Max(0.1, math.Min(1, 1-windSpeed/25))
 	humidityFactor := math.Max(0.1, math.Min(1, 1-float64(humidity)/100))
 	dewPointFactor := math.Max(0.1, math.Min(1, (10-tempDiff)/10))
 
-	weightedIndex := tempWeight*tempFactor +
-		windWeight*windFactor +
-		humidityWeight*humidityFactor +
-		dewPointWeight*dewPointFactor
+	weightedIndex := weights.Temperature*tempFactor +
+		weights.Wind*windFactor +
+		weights.Humidity*humidityFactor +
+		weights.DewPoint*dewPointFactor
 
 	return int(math.Round(math.Max(1, weightedIndex*5)))
 }
 
-func calculateSkyQualityIndex(clouds, humidity int, windSpeed, temp, dewPoint float64, seeing int) int {
+// calculateSkyQualityIndex rates an hour from 0 to 5 using the given weights.
+// The rating grows with the seeing index, which runs from 1 (turbulent) to 5 (steady).
+// A poor transparency lowers it, while an unknown one (0) leaves it unchanged.
+// A bright moon high in the sky lowers it as well.
+func calculateSkyQualityIndex(weights QualityWeights, clouds, humidity int, windSpeed, temp, dewPoint float64, seeing, transparency int, moon astro.MoonPosition) int {
 	tempDiff := math.Abs(temp - 15)
 	dewPointDiff := math.Abs(temp - dewPoint)
 
@@ -326,14 +426,21 @@ func calculateSkyQualityIndex(clouds, humidity int, windSpeed, temp, dewPoint fl
 	windFactor := 5 - windSpeed/10
 	tempFactor := 5 - tempDiff/10
 	dewPointFactor := 5 - dewPointDiff/5
-	seeingFactor := 5 - seeing
-
-	skyQualityIndex := 0.5*float64(cloudsFactor) +
-		0.2*float64(humidityFactor) +
-		0.2*windFactor +
-		0.1*tempFactor +
-		0.15*dewPointFactor +
-		0.5*float64(seeingFactor)
+	seeingFactor := seeing - 1
+
+	skyQualityIndex := weights.Clouds*float64(cloudsFactor) +
+		weights.Humidity*float64(humidityFactor) +
+		weights.Wind*windFactor +
+		weights.Temperature*tempFactor +
+		weights.DewPoint*dewPointFactor +
+		weights.Seeing*float64(seeingFactor)
+	if transparency > 0 {
+		skyQualityIndex -= weights.Transparency * float64(5-transparency)
+	}
+	if moon.Up() {
+		brightness := moon.Illumination / 100 * math.Sin(moon.Altitude*math.Pi/180)
+		skyQualityIndex -= weights.Moon * 5 * brightness
+	}
 
 	return int(math.Max(0, math.Min(5, skyQualityIndex)))
 }
@@ -348,13 +455,7 @@ func generateSeeingIndexForNight(nightForecastData []ForecastHour) int {
 	count := 0
 
 	for _, hour := range nightForecastData {
-		seeingIndex := calculateSeeingIndex(
-			hour.Temperature,
-			hour.DewPoint,
-			hour.WindSpeed,
-			hour.Humidity,
-		)
-		totalIndex += float64(seeingIndex)
+		totalIndex += float64(hour.Seeing)
 		count++
 	}
 
@@ -364,6 +465,19 @@ func generateSeeingIndexForNight(nightForecastData []ForecastHour) int {
 	return int(math.Round(totalIndex / float64(count)))
 }
 
+// calculateNightRating calculates the average sky quality rating of a night
+func calculateNightRating(nightForecastData []ForecastHour) int {
+	if len(nightForecastData) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, hour := range nightForecastData {
+		total += hour.Rating
+	}
+	return int(math.Round(float64(total) / float64(len(nightForecastData))))
+}
+
 // calculateWindDirectionAverage calculates the average wind direction using vector averaging
 func calculateWindDirectionAverage(data []ForecastHour) int {
 	var x, y float64
@@ -0,0 +1,52 @@
+package forecast
+
+import (
+	"testing"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+func TestSkyQualityIndexGrowsWithSeeing(t *testing.T) {
+	conditions := []struct {
+		name      string
+		clouds    int
+		humidity  int
+		windSpeed float64
+		temp      float64
+		dewPoint  float64
+		moon      astro.MoonPosition
+	}{
+		{"clear and dry", 0, 40, 5, 15, 2, astro.MoonPosition{}},
+		{"thin clouds", 20, 60, 10, 12, 6, astro.MoonPosition{}},
+		{"half clouded", 50, 80, 20, 5, 3, astro.MoonPosition{}},
+		{"full moon high", 10, 60, 5, 12, 4, astro.MoonPosition{Altitude: 60, Illumination: 100}},
+	}
+
+	for _, profile := range Profiles() {
+		for _, c := range conditions {
+			t.Run(profile.Name+"/"+c.name, func(t *testing.T) {
+				previous := -1
+				for seeing := 1; seeing <= 5; seeing++ {
+					rating := calculateSkyQualityIndex(profile.Quality, c.clouds, c.humidity, c.windSpeed, c.temp, c.dewPoint, seeing, 0, c.moon)
+					if rating < previous {
+						t.Errorf("rating with seeing %d = %d, lower than %d with seeing %d", seeing, rating, previous, seeing-1)
+					}
+					previous = rating
+				}
+			})
+		}
+	}
+}
+
+func TestSkyQualityIndexRewardsSteadyAir(t *testing.T) {
+	profile, err := ProfileByName("planetary")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steady := calculateSkyQualityIndex(profile.Quality, 0, 60, 5, 12, 4, 5, 0, astro.MoonPosition{})
+	turbulent := calculateSkyQualityIndex(profile.Quality, 0, 60, 5, 12, 4, 1, 0, astro.MoonPosition{})
+	if steady <= turbulent {
+		t.Errorf("planetary rating with steady air = %d, want more than %d with turbulent air", steady, turbulent)
+	}
+}
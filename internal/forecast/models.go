@@ -0,0 +1,106 @@
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/util"
+)
+
+// ModelHour compares the cloud cover forecast of several weather models for one hour
+type ModelHour struct {
+	DateTime   time.Time
+	CloudCover []int   // per model in the order of the comparison, negative when unknown
+	Mean       float64 // average over the models with a forecast
+	Agreement  int     // 0 to 100, -1 when fewer than two models have a forecast
+}
+
+// CompareModels lines the cloud cover of every model up hour by hour and scores how much
+// the models agree
+func CompareModels(comparison domain.ModelComparison) []ModelHour {
+	hours := make([]ModelHour, len(comparison.Time))
+	dateTimes := util.ParseHourlyTimes(comparison.Time, comparison.Location())
+
+	for i := range comparison.Time {
+		dateTime := dateTimes[i]
+
+		clouds := make([]int, len(comparison.Models))
+		for m, series := range comparison.Models {
+			clouds[m] = -1
+			if i < len(series.CloudCover) {
+				clouds[m] = series.CloudCover[i]
+			}
+		}
+
+		mean, agreement := calculateModelAgreement(clouds)
+		hours[i] = ModelHour{
+			DateTime:   dateTime,
+			CloudCover: clouds,
+			Mean:       mean,
+			Agreement:  agreement,
+		}
+	}
+
+	return hours
+}
+
+// calculateModelAgreement returns the mean cloud cover of the known forecasts and their agreement.
+// The agreement drops linearly with the mean absolute deviation from the mean: identical forecasts
+// score 100, and models split between clear and overcast score 0.
+func calculateModelAgreement(clouds []int) (float64, int) {
+	var sum float64
+	var count int
+	for _, value := range clouds {
+		if value >= 0 {
+			sum += float64(value)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, -1
+	}
+	mean := sum / float64(count)
+	if count < 2 {
+		return mean, -1
+	}
+
+	var deviation float64
+	for _, value := range clouds {
+		if value >= 0 {
+			deviation += math.Abs(float64(value) - mean)
+		}
+	}
+	deviation /= float64(count)
+
+	agreement := 100 * (1 - deviation/50)
+	return mean, int(math.Round(math.Max(0, math.Min(100, agreement))))
+}
+
+// ModelAgreement returns the average agreement of the hours where it is known, or -1
+func ModelAgreement(hours []ModelHour) int {
+	var total, count int
+	for _, hour := range hours {
+		if hour.Agreement >= 0 {
+			total += hour.Agreement
+			count++
+		}
+	}
+	if count == 0 {
+		return -1
+	}
+	return int(math.Round(float64(total) / float64(count)))
+}
+
+// NightModelAgreement returns the average agreement of the models between sunset and sunrise, or -1
+func NightModelAgreement(hours []ModelHour, sunInfo astro.SunInfo) int {
+	interval := sunInfo.Night()
+	var night []ModelHour
+	for _, hour := range hours {
+		if !hour.DateTime.Before(interval.Start) && !hour.DateTime.After(interval.End) {
+			night = append(night, hour)
+		}
+	}
+	return ModelAgreement(night)
+}
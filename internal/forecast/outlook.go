@@ -0,0 +1,45 @@
+package forecast
+
+import (
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+// NightOutlook holds the analysis of one night of the forecast horizon
+type NightOutlook struct {
+	Date  time.Time // day on which the night starts
+	Sun   astro.SunInfo
+	Moon  astro.MoonInfo
+	Night NightForecast
+}
+
+// AnalyzeNights analyzes every night covered by the forecast data, starting with tonight.
+// Nights for which no forecast hour falls between sunset and sunrise are left out.
+// Nights start on the calendar days of the site, in the time zone of the forecast hours.
+func AnalyzeNights(forecastData []ForecastHour, lat, lon float64, thresholds Thresholds) []NightOutlook {
+	if len(forecastData) == 0 {
+		return nil
+	}
+
+	first := forecastData[0].DateTime
+	last := forecastData[len(forecastData)-1].DateTime
+	day := time.Date(first.Year(), first.Month(), first.Day(), 12, 0, 0, 0, first.Location())
+
+	var nights []NightOutlook
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		sunInfo := astro.GetSunInfo(lat, lon, day)
+		if !CoversNight(forecastData, sunInfo) {
+			continue
+		}
+
+		nights = append(nights, NightOutlook{
+			Date:  day,
+			Sun:   sunInfo,
+			Moon:  astro.GetMoonInfo(lat, lon, sunInfo.Night().Start),
+			Night: AnalyzeNight(forecastData, lat, lon, sunInfo, thresholds),
+		})
+	}
+
+	return nights
+}
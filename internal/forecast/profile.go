@@ -0,0 +1,117 @@
+package forecast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultProfileName is the name of the general purpose scoring profile
+const DefaultProfileName = "default"
+
+// SeeingWeights weigh the surface factors of the seeing index. They only apply when the
+// pressure levels are missing: the upper-air estimate measures the turbulence itself, so
+// there are no proxies left to weigh.
+type SeeingWeights struct {
+	Temperature float64
+	Wind        float64
+	Humidity    float64
+	DewPoint    float64
+}
+
+// QualityWeights weigh the factors of the hourly sky quality rating
+type QualityWeights struct {
+	Clouds       float64
+	Humidity     float64
+	Wind         float64
+	Temperature  float64
+	DewPoint     float64
+	Seeing       float64
+	Transparency float64 // rating penalty per transparency step below 5
+	Moon         float64 // rating penalty of a full moon at the zenith, per rating step
+}
+
+// defaultCloudLayers counts mid clouds nearly fully and high clouds by half
+var defaultCloudLayers = CloudLayerWeights{Low: 1, Mid: 0.8, High: 0.5}
+
+// Profile tunes the forecast analysis to an observing style
+type Profile struct {
+	Name       string
+	Seeing     SeeingWeights
+	Quality    QualityWeights
+	Clouds     CloudLayerWeights // applied to the cloud cover of the rating and the window search
+	Thresholds Thresholds
+}
+
+// profiles lists the built-in scoring profiles
+var profiles = []Profile{
+	{
+		Name:       DefaultProfileName,
+		Seeing:     SeeingWeights{Temperature: 0.25, Wind: 0.4, Humidity: 0.15, DewPoint: 0.2},
+		Quality:    QualityWeights{Clouds: 0.5, Humidity: 0.2, Wind: 0.2, Temperature: 0.1, DewPoint: 0.15, Seeing: 0.5, Transparency: 0.2, Moon: 0.3},
+		Clouds:     defaultCloudLayers,
+		Thresholds: DefaultThresholds(),
+	},
+	{
+		// Visual deep-sky: transparency matters most, seeing hardly does, even cirrus veils faint objects
+		Name:       "deep-sky",
+		Seeing:     SeeingWeights{Temperature: 0.25, Wind: 0.4, Humidity: 0.15, DewPoint: 0.2},
+		Quality:    QualityWeights{Clouds: 0.6, Humidity: 0.3, Wind: 0.1, Temperature: 0.1, DewPoint: 0.15, Seeing: 0.2, Transparency: 0.5, Moon: 0.6},
+		Clouds:     CloudLayerWeights{Low: 1, Mid: 0.9, High: 0.7},
+		Thresholds: Thresholds{CloudCover: 20, MinWindowHours: 2, MaxMoonAltitude: 0, MinTargetAltitude: DefaultMinTargetAltitude, WindTolerance: DefaultWindTolerance},
+	},
+	{
+		// Planetary and lunar: steady air matters most, short gaps between clouds, thin cirrus and the moon are fine
+		Name:       "planetary",
+		Seeing:     SeeingWeights{Temperature: 0.3, Wind: 0.5, Humidity: 0.05, DewPoint: 0.15},
+		Quality:    QualityWeights{Clouds: 0.5, Humidity: 0.05, Wind: 0.25, Temperature: 0.1, DewPoint: 0.05, Seeing: 0.7},
+		Clouds:     CloudLayerWeights{Low: 1, Mid: 0.6, High: 0.25},
+		Thresholds: Thresholds{CloudCover: 50, MinWindowHours: 1, MaxMoonAltitude: 90, MinTargetAltitude: DefaultMinTargetAltitude, WindTolerance: DefaultWindTolerance},
+	},
+	{
+		// Wide-field astrophotography: long clear runs with little humidity and dew
+		Name:       "wide-field",
+		Seeing:     SeeingWeights{Temperature: 0.2, Wind: 0.3, Humidity: 0.25, DewPoint: 0.25},
+		Quality:    QualityWeights{Clouds: 0.6, Humidity: 0.25, Wind: 0.15, Temperature: 0.05, DewPoint: 0.25, Seeing: 0.1, Transparency: 0.5, Moon: 0.5},
+		Clouds:     CloudLayerWeights{Low: 1, Mid: 0.9, High: 0.8},
+		Thresholds: Thresholds{CloudCover: 15, MinWindowHours: 3, MaxMoonAltitude: 0, MinTargetAltitude: DefaultMinTargetAltitude, WindTolerance: DefaultWindTolerance},
+	},
+}
+
+// Profiles returns the built-in scoring profiles
+func Profiles() []Profile {
+	return append([]Profile(nil), profiles...)
+}
+
+// ProfileNames returns the names of the built-in scoring profiles
+func ProfileNames() []string {
+	names := make([]string, len(profiles))
+	for i, profile := range profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// DefaultProfile returns the general purpose scoring profile
+func DefaultProfile() Profile {
+	return profiles[0]
+}
+
+// ProfileByName looks a built-in profile up by name, ignoring case
+func ProfileByName(name string) (Profile, error) {
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (expected one of: %s)", name, strings.Join(ProfileNames(), ", "))
+}
+
+// NextProfile returns the built-in profile following the named one, wrapping around
+func NextProfile(name string) Profile {
+	for i, profile := range profiles {
+		if profile.Name == name {
+			return profiles[(i+1)%len(profiles)]
+		}
+	}
+	return DefaultProfile()
+}
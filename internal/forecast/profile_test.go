@@ -0,0 +1,28 @@
+package forecast
+
+import "testing"
+
+func TestProfileThresholds(t *testing.T) {
+	for _, name := range ProfileNames() {
+		t.Run(name, func(t *testing.T) {
+			profile, err := ProfileByName(name)
+			if err != nil {
+				t.Fatalf("ProfileByName(%q) failed: %v", name, err)
+			}
+			thresholds := profile.Thresholds
+
+			if _, err := WindToleranceByName(string(thresholds.WindTolerance)); err != nil {
+				t.Errorf("WindTolerance = %q, want a known tolerance", thresholds.WindTolerance)
+			}
+			if thresholds.CloudCover <= 0 || thresholds.CloudCover > 100 {
+				t.Errorf("CloudCover = %d, want within (0, 100]", thresholds.CloudCover)
+			}
+			if thresholds.MinWindowHours < 1 {
+				t.Errorf("MinWindowHours = %d, want at least 1", thresholds.MinWindowHours)
+			}
+			if thresholds.MinTargetAltitude <= 0 || thresholds.MinTargetAltitude >= 90 {
+				t.Errorf("MinTargetAltitude = %v, want within (0, 90)", thresholds.MinTargetAltitude)
+			}
+		})
+	}
+}
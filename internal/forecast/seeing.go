@@ -0,0 +1,123 @@
+package forecast
+
+import (
+	"math"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+// pressureLevel holds the upper-air conditions at one pressure level
+type pressureLevel struct {
+	height      float64 // standard atmosphere altitude in meters
+	windSpeed   float64 // in m/s
+	temperature float64 // in kelvin
+}
+
+// Upper-air seeing model coefficients, in arcseconds
+const (
+	seeingBaseline     = 0.5   // ground and dome contribution on a calm night
+	seeingJetFactor    = 0.015 // per m/s of jet stream wind
+	seeingShearFactor  = 0.15  // per unit of the root turbulence integral
+	seeingSurfaceWind  = 0.03  // per m/s of surface wind
+	dryAdiabaticLapse  = 0.0098
+	gravity            = 9.81
+	kmhToMs            = 1 / 3.6
+	celsiusToKelvin    = 273.15
+	minUpperAirLevels  = 3
+	jetStreamMinHeight = 9000.0
+)
+
+// upperAirLevels extracts the pressure level profile of hour i, from the lowest to the highest level.
+// Levels missing from the data, or null for this hour, are left out.
+func upperAirLevels(hourly domain.HourlyWeather, i int) []pressureLevel {
+	series := []struct {
+		height      float64
+		windSpeed   []*float64
+		temperature []*float64
+	}{
+		{1457, hourly.WindSpeed850hPa, hourly.Temperature850hPa},
+		{3012, hourly.WindSpeed700hPa, hourly.Temperature700hPa},
+		{5574, hourly.WindSpeed500hPa, hourly.Temperature500hPa},
+		{9164, hourly.WindSpeed300hPa, hourly.Temperature300hPa},
+		{10363, hourly.WindSpeed250hPa, hourly.Temperature250hPa},
+		{11784, hourly.WindSpeed200hPa, hourly.Temperature200hPa},
+	}
+
+	var levels []pressureLevel
+	for _, s := range series {
+		if i >= len(s.windSpeed) || i >= len(s.temperature) || s.windSpeed[i] == nil || s.temperature[i] == nil {
+			continue
+		}
+		levels = append(levels, pressureLevel{
+			height:      s.height,
+			windSpeed:   *s.windSpeed[i] * kmhToMs,
+			temperature: *s.temperature[i] + celsiusToKelvin,
+		})
+	}
+	return levels
+}
+
+// calculateUpperAirSeeing estimates the seeing in arcseconds from the pressure level profile
+// and the surface wind in km/h. It returns false when the profile is too sparse.
+//
+// Optical turbulence grows with the wind shear between levels, damped where the air is
+// stable (high Richardson number), and with the strength of the jet stream itself.
+func calculateUpperAirSeeing(levels []pressureLevel, surfaceWindSpeed float64) (float64, bool) {
+	if len(levels) < minUpperAirLevels {
+		return 0, false
+	}
+
+	var turbulence float64
+	for i := 1; i < len(levels); i++ {
+		lower, upper := levels[i-1], levels[i]
+		dz := upper.height - lower.height
+
+		shear := (upper.windSpeed - lower.windSpeed) / dz
+		meanTemperature := (upper.temperature + lower.temperature) / 2
+		brunt := gravity / meanTemperature * ((upper.temperature-lower.temperature)/dz + dryAdiabaticLapse)
+
+		richardson := math.Inf(1)
+		if shear != 0 {
+			richardson = brunt / (shear * shear)
+		}
+		stability := 1 / (1 + math.Max(0, richardson))
+
+		shearPerKm := shear * 1000
+		turbulence += shearPerKm * shearPerKm * dz / 1000 * stability
+	}
+
+	seeing := seeingBaseline +
+		seeingJetFactor*jetStreamSpeed(levels)*kmhToMs +
+		seeingShearFactor*math.Sqrt(turbulence) +
+		seeingSurfaceWind*surfaceWindSpeed*kmhToMs
+
+	return seeing, true
+}
+
+// jetStreamSpeed returns the strongest wind above 9 km in km/h
+func jetStreamSpeed(levels []pressureLevel) float64 {
+	jetStream := 0.0
+	for _, level := range levels {
+		if level.height >= jetStreamMinHeight {
+			jetStream = math.Max(jetStream, level.windSpeed/kmhToMs)
+		}
+	}
+	return jetStream
+}
+
+// seeingIndexFromArcsec maps a seeing estimate in arcseconds to the seeing index, from 1
+// (turbulent) to 5 (steady)
+func seeingIndexFromArcsec(arcsec float64) int {
+	switch {
+	case arcsec <= 1:
+		return 5
+	case arcsec <= 1.5:
+		return 4
+	case arcsec <= 2:
+		return 3
+	case arcsec <= 3:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,111 @@
+package forecast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+// profileLevels builds a pressure level profile with the given wind speeds in m/s at
+// 850, 700, 500, 300, 250 and 200 hPa, in a standard temperature gradient
+func profileLevels(winds ...float64) []pressureLevel {
+	heights := []float64{1457, 3012, 5574, 9164, 10363, 11784}
+	temperatures := []float64{281, 271, 253, 229, 221, 217}
+
+	levels := make([]pressureLevel, len(winds))
+	for i, wind := range winds {
+		levels[i] = pressureLevel{height: heights[i], windSpeed: wind, temperature: temperatures[i]}
+	}
+	return levels
+}
+
+func TestUpperAirSeeing(t *testing.T) {
+	calm, ok := calculateUpperAirSeeing(profileLevels(3, 5, 8, 12, 14, 12), 5)
+	if !ok {
+		t.Fatal("calm profile: no seeing estimate")
+	}
+	jetStream, ok := calculateUpperAirSeeing(profileLevels(5, 15, 30, 60, 70, 55), 5)
+	if !ok {
+		t.Fatal("jet stream profile: no seeing estimate")
+	}
+
+	if calm < seeingBaseline {
+		t.Errorf("calm seeing = %.2f\", below the %.2f\" baseline", calm, seeingBaseline)
+	}
+	if jetStream <= calm {
+		t.Errorf("jet stream seeing = %.2f\", want worse than calm %.2f\"", jetStream, calm)
+	}
+	if calmIndex, jetIndex := seeingIndexFromArcsec(calm), seeingIndexFromArcsec(jetStream); calmIndex <= jetIndex {
+		t.Errorf("calm seeing index = %d, want better than jet stream index %d", calmIndex, jetIndex)
+	}
+
+	windy, _ := calculateUpperAirSeeing(profileLevels(3, 5, 8, 12, 14, 12), 40)
+	if windy <= calm {
+		t.Errorf("seeing with surface wind = %.2f\", want worse than calm %.2f\"", windy, calm)
+	}
+}
+
+func TestUpperAirSeeingNeedsLevels(t *testing.T) {
+	if _, ok := calculateUpperAirSeeing(profileLevels(3, 5), 5); ok {
+		t.Error("seeing estimated from two levels")
+	}
+	if _, ok := calculateUpperAirSeeing(nil, 5); ok {
+		t.Error("seeing estimated without levels")
+	}
+}
+
+func TestUpperAirLevelsSkipsMissingData(t *testing.T) {
+	var hourly domain.HourlyWeather
+	response := `{
+		"wind_speed_850hPa": [36, 36],
+		"temperature_850hPa": [10, 10],
+		"wind_speed_700hPa": [54, null],
+		"temperature_700hPa": [null, 0],
+		"wind_speed_500hPa": [72, 72],
+		"temperature_500hPa": [-20, -20]
+	}`
+	if err := json.Unmarshal([]byte(response), &hourly); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 2 {
+		levels := upperAirLevels(hourly, i)
+		if len(levels) != 2 {
+			t.Fatalf("hour %d: len(levels) = %d, want 2", i, len(levels))
+		}
+		if levels[0].windSpeed != 10 || levels[0].temperature != 283.15 {
+			t.Errorf("hour %d: levels[0] = %+v, want 10 m/s and 283.15 K", i, levels[0])
+		}
+		if levels[1].height != 5574 {
+			t.Errorf("hour %d: levels[1] at %v m, want the 500 hPa level", i, levels[1].height)
+		}
+	}
+	if len(upperAirLevels(hourly, 2)) != 0 {
+		t.Error("levels found past the end of the data")
+	}
+}
+
+func TestSeeingIndexFromArcsec(t *testing.T) {
+	tests := []struct {
+		arcsec float64
+		want   int
+	}{
+		{0.6, 5},
+		{1, 5},
+		{1.01, 4},
+		{1.5, 4},
+		{1.51, 3},
+		{2, 3},
+		{2.01, 2},
+		{3, 2},
+		{3.01, 1},
+		{6, 1},
+	}
+
+	for _, tt := range tests {
+		if got := seeingIndexFromArcsec(tt.arcsec); got != tt.want {
+			t.Errorf("seeingIndexFromArcsec(%g) = %d, want %d", tt.arcsec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package forecast
+
+import (
+	"math"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+// Transparency model coefficients, in units of optical depth at 550 nm
+const (
+	dustExtinction        = 0.002 // per µg/m³ of dust near the ground
+	fineParticleHaze      = 0.004 // per µg/m³ of PM2.5, the low haze hiding the horizon
+	humidHazeMax          = 0.3   // haze added by saturated air aloft
+	humidHazeThreshold    = 60.0  // mid-level relative humidity in percent from which haze forms
+	particlesOnlyBaseline = 0.1   // background aerosol optical depth when only particles are known
+)
+
+// airQualityHour holds the aerosol forecast of one hour
+type airQualityHour struct {
+	aerosolOpticalDepth float64
+	dust                float64
+	pm25                float64
+	hasOpticalDepth     bool
+}
+
+// airQualityByTime indexes the hourly air-quality forecast by its time string.
+// It returns nil when there is no air-quality data, and leaves out the hours where
+// every aerosol value is null.
+func airQualityByTime(airQuality *domain.AirQualityData) map[string]airQualityHour {
+	if airQuality == nil {
+		return nil
+	}
+
+	hourly := airQuality.Hourly
+	hours := make(map[string]airQualityHour, len(hourly.Time))
+	for i, t := range hourly.Time {
+		var hour airQualityHour
+		known := false
+		if value, ok := valueAt(hourly.AerosolOpticalDepth, i); ok {
+			hour.aerosolOpticalDepth = value
+			hour.hasOpticalDepth = true
+			known = true
+		}
+		if value, ok := valueAt(hourly.Dust, i); ok {
+			hour.dust = value
+			known = true
+		}
+		if value, ok := valueAt(hourly.PM25, i); ok {
+			hour.pm25 = value
+			known = true
+		}
+		if known {
+			hours[t] = hour
+		}
+	}
+	return hours
+}
+
+// valueAt returns the value of hour i of a nullable series, and false when it is null or missing
+func valueAt(series []*float64, i int) (float64, bool) {
+	if i >= len(series) || series[i] == nil {
+		return 0, false
+	}
+	return *series[i], true
+}
+
+// humidityAloft returns the mean relative humidity at 700 and 500 hPa of hour i, over the
+// levels that are not null
+func humidityAloft(hourly domain.HourlyWeather, i int) (float64, bool) {
+	var sum float64
+	var count int
+	for _, level := range [][]*float64{hourly.Humidity700hPa, hourly.Humidity500hPa} {
+		if value, ok := valueAt(level, i); ok {
+			sum += value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// calculateTransparency estimates the optical depth of the atmosphere from the aerosols and
+// the humidity aloft and maps it to a 1 to 5 transparency index. It returns 0 when neither is known.
+//
+// Dust outbreaks and wildfire smoke raise the aerosol optical depth well above its usual
+// 0.1, and humid mid levels grow haze and thin cirrus that the cloud cover misses.
+func calculateTransparency(aerosols *airQualityHour, humidity float64, hasHumidity bool) (int, float64) {
+	if aerosols == nil && !hasHumidity {
+		return 0, 0
+	}
+
+	opticalDepth := particlesOnlyBaseline
+	if aerosols != nil {
+		if aerosols.hasOpticalDepth {
+			opticalDepth = aerosols.aerosolOpticalDepth
+		}
+		opticalDepth += dustExtinction*aerosols.dust + fineParticleHaze*aerosols.pm25
+	}
+	if hasHumidity {
+		opticalDepth += humidHazeMax * math.Max(0, humidity-humidHazeThreshold) / (100 - humidHazeThreshold)
+	}
+
+	return transparencyIndexFromOpticalDepth(opticalDepth), opticalDepth
+}
+
+// transparencyIndexFromOpticalDepth maps an optical depth to the 1 to 5 transparency index
+func transparencyIndexFromOpticalDepth(opticalDepth float64) int {
+	switch {
+	case opticalDepth <= 0.1:
+		return 5
+	case opticalDepth <= 0.2:
+		return 4
+	case opticalDepth <= 0.35:
+		return 3
+	case opticalDepth <= 0.6:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// generateTransparencyIndexForNight calculates the average transparency index of the hours
+// where it is known, or 0 when it is unknown for the whole night
+func generateTransparencyIndexForNight(nightForecastData []ForecastHour) int {
+	var total, count int
+	for _, hour := range nightForecastData {
+		if hour.Transparency > 0 {
+			total += hour.Transparency
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return int(math.Round(float64(total) / float64(count)))
+}
@@ -0,0 +1,40 @@
+package forecast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+func TestNullAerosolsAndHumidityAreUnknown(t *testing.T) {
+	var data domain.WeatherData
+	response := `{"latitude": 45, "longitude": 5, "timezone": "UTC",
+		"hourly": {
+			"time": ["2024-11-05T22:00", "2024-11-05T23:00", "2024-11-06T00:00"],
+			"relative_humidity_700hPa": [null, null, 90],
+			"relative_humidity_500hPa": [null, 100, null]
+		},
+		"air_quality": {"hourly": {
+			"time": ["2024-11-05T22:00", "2024-11-05T23:00", "2024-11-06T00:00"],
+			"aerosol_optical_depth": [null, null, 0.05],
+			"dust": [null, null, 0],
+			"pm2_5": [null, null, 0]
+		}}
+	}`
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	hours := GenerateForecastData(data)
+	if hours[0].Transparency != 0 || hours[0].OpticalDepth != 0 {
+		t.Errorf("null aerosols and humidity give transparency %d (%.2f), want unknown", hours[0].Transparency, hours[0].OpticalDepth)
+	}
+	// Saturated air at 500 hPa alone, over the background aerosols
+	if want := particlesOnlyBaseline + humidHazeMax; hours[1].OpticalDepth != want {
+		t.Errorf("optical depth with only the 500 hPa humidity = %.2f, want %.2f", hours[1].OpticalDepth, want)
+	}
+	if want := 0.05 + humidHazeMax*0.75; hours[2].OpticalDepth != want {
+		t.Errorf("optical depth with the 700 hPa humidity = %.3f, want %.3f", hours[2].OpticalDepth, want)
+	}
+}
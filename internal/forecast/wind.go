@@ -0,0 +1,102 @@
+package forecast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShakeRisk is the likelihood of wind gusts shaking the telescope
+type ShakeRisk int
+
+const (
+	ShakeRiskNone ShakeRisk = iota
+	ShakeRiskLow
+	ShakeRiskModerate
+	ShakeRiskHigh
+)
+
+// String returns the identifier of the risk level
+func (r ShakeRisk) String() string {
+	switch r {
+	case ShakeRiskLow:
+		return "low"
+	case ShakeRiskModerate:
+		return "moderate"
+	case ShakeRiskHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// WindTolerance describes how well the observer's setup withstands the wind
+type WindTolerance string
+
+const (
+	// WindToleranceOpen is an exposed telescope, such as a Dobsonian or a long refractor in the open
+	WindToleranceOpen WindTolerance = "open"
+	// WindToleranceSheltered is a telescope behind a windbreak or in a roll-off roof shed
+	WindToleranceSheltered WindTolerance = "sheltered"
+	// WindToleranceDome is a telescope in an observatory dome
+	WindToleranceDome WindTolerance = "dome"
+)
+
+// DefaultWindTolerance is the wind tolerance used when none is configured
+const DefaultWindTolerance = WindToleranceOpen
+
+// gustLimits are the gusts in km/h from which the shake risk is low, moderate and high
+var gustLimits = map[WindTolerance][3]float64{
+	WindToleranceOpen:      {10, 20, 30},
+	WindToleranceSheltered: {20, 30, 45},
+	WindToleranceDome:      {30, 45, 60},
+}
+
+// WindToleranceNames returns the names of the wind tolerances
+func WindToleranceNames() []string {
+	return []string{string(WindToleranceOpen), string(WindToleranceSheltered), string(WindToleranceDome)}
+}
+
+// WindToleranceByName looks a wind tolerance up by name, ignoring case
+func WindToleranceByName(name string) (WindTolerance, error) {
+	tolerance := WindTolerance(strings.ToLower(name))
+	if _, ok := gustLimits[tolerance]; !ok {
+		return "", fmt.Errorf("unknown wind tolerance %q (expected one of: %s)", name, strings.Join(WindToleranceNames(), ", "))
+	}
+	return tolerance, nil
+}
+
+// calculateShakeRisk rates how much the gusts of an hour shake a telescope with the given tolerance.
+// The mean wind is used when the gusts are unknown. An empty tolerance is the default one.
+func calculateShakeRisk(tolerance WindTolerance, gusts, windSpeed float64) ShakeRisk {
+	limits, ok := gustLimits[tolerance]
+	if !ok {
+		limits = gustLimits[DefaultWindTolerance]
+	}
+	wind := max(gusts, windSpeed)
+
+	switch {
+	case wind >= limits[2]:
+		return ShakeRiskHigh
+	case wind >= limits[1]:
+		return ShakeRiskModerate
+	case wind >= limits[0]:
+		return ShakeRiskLow
+	default:
+		return ShakeRiskNone
+	}
+}
+
+// gustSummary returns the strongest gusts of the night in km/h and the highest shake risk
+func gustSummary(nightForecastData []ForecastHour) (float64, ShakeRisk) {
+	var gusts float64
+	var risk ShakeRisk
+
+	for _, hour := range nightForecastData {
+		gusts = max(gusts, hour.WindGusts)
+		if hour.ShakeRisk > risk {
+			risk = hour.ShakeRisk
+		}
+	}
+
+	return gusts, risk
+}
@@ -0,0 +1,80 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateShakeRisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerance WindTolerance
+		gusts     float64
+		windSpeed float64
+		want      ShakeRisk
+	}{
+		{"open calm", WindToleranceOpen, 9.9, 5, ShakeRiskNone},
+		{"open low limit", WindToleranceOpen, 10, 5, ShakeRiskLow},
+		{"open moderate limit", WindToleranceOpen, 20, 10, ShakeRiskModerate},
+		{"open high limit", WindToleranceOpen, 30, 15, ShakeRiskHigh},
+		{"sheltered breeze", WindToleranceSheltered, 19.9, 10, ShakeRiskNone},
+		{"sheltered gusts", WindToleranceSheltered, 25, 10, ShakeRiskLow},
+		{"sheltered storm", WindToleranceSheltered, 45, 25, ShakeRiskHigh},
+		{"dome gusts", WindToleranceDome, 44.9, 20, ShakeRiskLow},
+		{"dome moderate limit", WindToleranceDome, 45, 20, ShakeRiskModerate},
+		{"dome storm", WindToleranceDome, 60, 30, ShakeRiskHigh},
+		{"unknown gusts", WindToleranceOpen, 0, 22, ShakeRiskModerate},
+		{"empty tolerance", "", 25, 10, ShakeRiskModerate},
+		{"unknown tolerance", "garage", 25, 10, ShakeRiskModerate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateShakeRisk(tt.tolerance, tt.gusts, tt.windSpeed); got != tt.want {
+				t.Errorf("calculateShakeRisk(%q, %v, %v) = %v, want %v", tt.tolerance, tt.gusts, tt.windSpeed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindToleranceByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    WindTolerance
+		wantErr bool
+	}{
+		{name: "open", want: WindToleranceOpen},
+		{name: "Sheltered", want: WindToleranceSheltered},
+		{name: "DOME", want: WindToleranceDome},
+		{name: "garage", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WindToleranceByName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WindToleranceByName(%q) error = %v, want error %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("WindToleranceByName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGustSummary(t *testing.T) {
+	start := time.Date(2024, time.March, 10, 21, 0, 0, 0, time.UTC)
+	hours := []ForecastHour{
+		{DateTime: start, WindGusts: 12, ShakeRisk: ShakeRiskLow},
+		{DateTime: start.Add(time.Hour), WindGusts: 35, ShakeRisk: ShakeRiskModerate},
+		{DateTime: start.Add(2 * time.Hour), WindGusts: 8, ShakeRisk: ShakeRiskNone},
+	}
+
+	if gusts, risk := gustSummary(hours); gusts != 35 || risk != ShakeRiskModerate {
+		t.Errorf("gustSummary = %v, %v, want 35, moderate", gusts, risk)
+	}
+	if gusts, risk := gustSummary(nil); gusts != 0 || risk != ShakeRiskNone {
+		t.Errorf("gustSummary(nil) = %v, %v, want 0, none", gusts, risk)
+	}
+}
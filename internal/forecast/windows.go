@@ -0,0 +1,90 @@
+package forecast
+
+import (
+	"sort"
+	"time"
+)
+
+// ObservationWindow is a run of consecutive clear and dark forecast hours
+type ObservationWindow struct {
+	Start      time.Time // beginning of the first hour
+	End        time.Time // end of the last hour
+	CloudCover float64   // average effective cloud cover percentage
+	Seeing     float64   // average seeing index
+	Rating     float64   // average sky quality rating
+	Confidence int       // percent of ensemble members clear over the window, -1 when unknown
+}
+
+// Duration returns the length of the window
+func (w ObservationWindow) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// Hours returns the number of forecast hours covered by the window
+func (w ObservationWindow) Hours() int {
+	return int(w.Duration() / time.Hour)
+}
+
+// FindObservationWindows returns every run of at least thresholds.MinWindowHours consecutive
+// hours with an effective cloud cover at or below thresholds.CloudCover, gusts below the
+// high shake risk of thresholds.WindTolerance and a low fog probability, ranked from best to worst
+func FindObservationWindows(data []ForecastHour, thresholds Thresholds) []ObservationWindow {
+	var windows []ObservationWindow
+	var run []ForecastHour
+
+	flush := func() {
+		if len(run) >= thresholds.MinWindowHours && len(run) > 0 {
+			windows = append(windows, newObservationWindow(run, thresholds.CloudCover))
+		}
+		run = nil
+	}
+
+	for _, hour := range data {
+		if hour.EffectiveClouds > thresholds.CloudCover || hour.ShakeRisk >= ShakeRiskHigh || hour.FogProbability >= highFogProbability {
+			flush()
+			continue
+		}
+		if len(run) > 0 && !hour.DateTime.Equal(run[len(run)-1].DateTime.Add(time.Hour)) {
+			flush()
+		}
+		run = append(run, hour)
+	}
+	flush()
+
+	rankObservationWindows(windows)
+	return windows
+}
+
+// newObservationWindow summarizes a run of consecutive forecast hours
+func newObservationWindow(hours []ForecastHour, cloudCoverThreshold int) ObservationWindow {
+	var clouds, seeing, rating float64
+	for _, hour := range hours {
+		clouds += float64(hour.EffectiveClouds)
+		seeing += float64(hour.Seeing)
+		rating += float64(hour.Rating)
+	}
+	count := float64(len(hours))
+
+	return ObservationWindow{
+		Start:      hours[0].DateTime,
+		End:        hours[len(hours)-1].DateTime.Add(time.Hour),
+		CloudCover: clouds / count,
+		Seeing:     seeing / count,
+		Rating:     rating / count,
+		Confidence: calculateWindowConfidence(hours, cloudCoverThreshold),
+	}
+}
+
+// rankObservationWindows orders windows by length, then rating, then cloud cover
+func rankObservationWindows(windows []ObservationWindow) {
+	sort.SliceStable(windows, func(i, j int) bool {
+		a, b := windows[i], windows[j]
+		if a.Duration() != b.Duration() {
+			return a.Duration() > b.Duration()
+		}
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		return a.CloudCover < b.CloudCover
+	})
+}
@@ -0,0 +1,150 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+// windowHour returns a clear hour of the night starting at 18:00 UTC on 2024-03-10, offset hours later
+func windowHour(offset, effectiveClouds, rating int) ForecastHour {
+	t := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC).Add(time.Duration(offset) * time.Hour)
+	return ForecastHour{
+		DateTime:        t,
+		Hour:            t.Hour(),
+		Clouds:          100,
+		EffectiveClouds: effectiveClouds,
+		Rating:          rating,
+		Seeing:          3,
+	}
+}
+
+func TestFindObservationWindows(t *testing.T) {
+	thresholds := Thresholds{CloudCover: 20, MinWindowHours: 2}
+	start := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+
+	windy := windowHour(3, 0, 4)
+	windy.ShakeRisk = ShakeRiskHigh
+	foggy := windowHour(3, 0, 4)
+	foggy.FogProbability = highFogProbability
+
+	tests := []struct {
+		name      string
+		hours     []ForecastHour
+		wantSpans [][2]int // start and end offsets of each window, best first
+	}{
+		{
+			name:      "across midnight",
+			hours:     []ForecastHour{windowHour(4, 0, 4), windowHour(5, 0, 4), windowHour(6, 0, 4), windowHour(7, 0, 4)},
+			wantSpans: [][2]int{{4, 8}},
+		},
+		{
+			name:      "gap in the data",
+			hours:     []ForecastHour{windowHour(1, 0, 4), windowHour(2, 0, 4), windowHour(4, 0, 4), windowHour(5, 0, 4)},
+			wantSpans: [][2]int{{1, 3}, {4, 6}},
+		},
+		{
+			name:      "too short",
+			hours:     []ForecastHour{windowHour(1, 0, 4), windowHour(2, 50, 4), windowHour(3, 0, 4), windowHour(4, 0, 4)},
+			wantSpans: [][2]int{{3, 5}},
+		},
+		{
+			name:      "cloudy hour",
+			hours:     []ForecastHour{windowHour(1, 0, 4), windowHour(2, 0, 4), windowHour(3, 21, 4), windowHour(4, 0, 4), windowHour(5, 0, 4)},
+			wantSpans: [][2]int{{1, 3}, {4, 6}},
+		},
+		{
+			name:      "strong gusts",
+			hours:     []ForecastHour{windowHour(1, 0, 4), windowHour(2, 0, 4), windy, windowHour(4, 0, 4), windowHour(5, 0, 4), windowHour(6, 0, 4)},
+			wantSpans: [][2]int{{4, 7}, {1, 3}},
+		},
+		{
+			name:      "fog",
+			hours:     []ForecastHour{windowHour(1, 0, 4), windowHour(2, 0, 4), foggy, windowHour(4, 0, 4)},
+			wantSpans: [][2]int{{1, 3}},
+		},
+		{
+			name:      "no clear hour",
+			hours:     []ForecastHour{windowHour(1, 80, 1), windowHour(2, 90, 1)},
+			wantSpans: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := FindObservationWindows(tt.hours, thresholds)
+			if len(windows) != len(tt.wantSpans) {
+				t.Fatalf("got %d windows, want %d", len(windows), len(tt.wantSpans))
+			}
+			for i, span := range tt.wantSpans {
+				wantStart := start.Add(time.Duration(span[0]) * time.Hour)
+				wantEnd := start.Add(time.Duration(span[1]) * time.Hour)
+				if !windows[i].Start.Equal(wantStart) || !windows[i].End.Equal(wantEnd) {
+					t.Errorf("window %d = %v to %v, want %v to %v", i, windows[i].Start, windows[i].End, wantStart, wantEnd)
+				}
+				if windows[i].Hours() != span[1]-span[0] {
+					t.Errorf("window %d Hours() = %d, want %d", i, windows[i].Hours(), span[1]-span[0])
+				}
+			}
+		})
+	}
+}
+
+func TestObservationWindowAverages(t *testing.T) {
+	hours := []ForecastHour{windowHour(1, 10, 3), windowHour(2, 20, 5)}
+	hours[1].Seeing = 5
+
+	windows := FindObservationWindows(hours, Thresholds{CloudCover: 20, MinWindowHours: 1})
+	if len(windows) != 1 {
+		t.Fatalf("got %d windows, want 1", len(windows))
+	}
+	window := windows[0]
+	if window.CloudCover != 15 {
+		t.Errorf("CloudCover = %v, want 15 from the effective cloud cover", window.CloudCover)
+	}
+	if window.Rating != 4 || window.Seeing != 4 {
+		t.Errorf("Rating, Seeing = %v, %v, want 4, 4", window.Rating, window.Seeing)
+	}
+	if window.Confidence != unknownProbability {
+		t.Errorf("Confidence = %d, want %d without ensemble", window.Confidence, unknownProbability)
+	}
+}
+
+func TestRankObservationWindows(t *testing.T) {
+	start := time.Date(2024, time.March, 10, 20, 0, 0, 0, time.UTC)
+	window := func(hours int, rating, clouds float64) ObservationWindow {
+		return ObservationWindow{Start: start, End: start.Add(time.Duration(hours) * time.Hour), Rating: rating, CloudCover: clouds}
+	}
+
+	tests := []struct {
+		name    string
+		windows []ObservationWindow
+		want    []ObservationWindow
+	}{
+		{
+			name:    "longest first",
+			windows: []ObservationWindow{window(2, 5, 0), window(4, 2, 20)},
+			want:    []ObservationWindow{window(4, 2, 20), window(2, 5, 0)},
+		},
+		{
+			name:    "best rated on equal length",
+			windows: []ObservationWindow{window(3, 3, 0), window(3, 4, 10)},
+			want:    []ObservationWindow{window(3, 4, 10), window(3, 3, 0)},
+		},
+		{
+			name:    "clearest on equal length and rating",
+			windows: []ObservationWindow{window(3, 4, 15), window(3, 4, 5)},
+			want:    []ObservationWindow{window(3, 4, 5), window(3, 4, 15)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rankObservationWindows(tt.windows)
+			for i := range tt.want {
+				if tt.windows[i] != tt.want[i] {
+					t.Errorf("window %d = %+v, want %+v", i, tt.windows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package format
+
+import (
+	"fmt"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/i18n"
+)
+
+// Unknown stands for a value that is not known
+const Unknown = "–"
+
+// Time shows the time of day of t in loc, or --:-- when t is zero
+func Time(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "--:--"
+	}
+	return t.In(loc).Format("15:04")
+}
+
+// Period shows the times of day a period starts and ends in loc
+func Period(start, end time.Time, loc *time.Location) string {
+	return Time(start, loc) + "–" + Time(end, loc)
+}
+
+// Intervals shows the first of a series of periods and how many more follow
+func Intervals(intervals []astro.Interval, loc *time.Location) string {
+	period := Period(intervals[0].Start, intervals[0].End, loc)
+	if len(intervals) > 1 {
+		period += fmt.Sprintf(" +%d", len(intervals)-1)
+	}
+	return period
+}
+
+// Percent shows a percentage, or Unknown when it is negative
+func Percent(percent int) string {
+	if percent < 0 {
+		return Unknown
+	}
+	return fmt.Sprintf("%d%%", percent)
+}
+
+// KnownPercent shows a percentage, or Unknown when it is nil
+func KnownPercent(percent *int) string {
+	if percent == nil {
+		return Unknown
+	}
+	return Percent(*percent)
+}
+
+// Seeing shows the seeing index with the estimated seeing in arcseconds when known
+func Seeing(index int, arcsec float64) string {
+	if arcsec == 0 {
+		return fmt.Sprintf("%d/5", index)
+	}
+	return fmt.Sprintf("%d/5 %.1f\"", index, arcsec)
+}
+
+// TransparencyIndex shows a transparency index, or Unknown when it is 0
+func TransparencyIndex(index int) string {
+	if index == 0 {
+		return Unknown
+	}
+	return fmt.Sprintf("%d/5", index)
+}
+
+// Transparency describes the transparency of the night
+func Transparency(index int) string {
+	if index == 0 {
+		return i18n.T("weather.transparency_unknown", nil)
+	}
+	return i18n.T("weather.transparency", map[string]any{"Transparency": index})
+}
+
+// cloudLayerBars are the bar heights of a cloud layer, from clear to overcast
+var cloudLayerBars = []rune("·▁▂▃▄▅▆▇█")
+
+// CloudLayers shows the low, mid and high cloud cover side by side as a stacked bar
+// followed by their percentages
+func CloudLayers(low, mid, high int) string {
+	bars := make([]rune, 0, 3)
+	for _, cover := range []int{low, mid, high} {
+		level := max(0, min(len(cloudLayerBars)-1, (cover*(len(cloudLayerBars)-1)+50)/100))
+		bars = append(bars, cloudLayerBars[level])
+	}
+	return fmt.Sprintf("%s %d/%d/%d", string(bars), low, mid, high)
+}
+
+// MoonPosition shows the moon phase and altitude, or Unknown when the moon is down
+func MoonPosition(moon astro.MoonPosition) string {
+	if !moon.Up() {
+		return Unknown
+	}
+	return fmt.Sprintf("%s %.0f°", moon.PhaseEmoji, moon.Altitude)
+}
+
+// Elongation shows the angular distance of a planet from the sun and on which side it lies
+func Elongation(elongation float64) string {
+	if elongation < 0 {
+		return fmt.Sprintf("%.0f° %s", -elongation, i18n.T("planets.west", nil))
+	}
+	return fmt.Sprintf("%.0f° %s", elongation, i18n.T("planets.east", nil))
+}
+
+// PlanetClear shows when an observable planet can be seen in a clear window: the first
+// period and how many more follow, whether it is clouded out, Unknown when it is too low
+// or a question mark when the night is not forecast yet
+func PlanetClear(observable bool, clear []astro.Interval, forecasted bool, loc *time.Location) string {
+	switch {
+	case !observable:
+		return Unknown
+	case !forecasted:
+		return "?"
+	case len(clear) == 0:
+		return i18n.T("planets.clouded", nil)
+	}
+	return Intervals(clear, loc)
+}
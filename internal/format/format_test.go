@@ -0,0 +1,81 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+)
+
+func TestTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, time.November, 5, 21, 30, 0, 0, time.UTC)
+
+	if got := Time(at, paris); got != "22:30" {
+		t.Errorf("Time in Paris = %q, want 22:30", got)
+	}
+	if got := Time(time.Time{}, paris); got != "--:--" {
+		t.Errorf("Time of the zero time = %q, want --:--", got)
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	start := time.Date(2024, time.November, 5, 20, 0, 0, 0, time.UTC)
+	first := astro.Interval{Start: start, End: start.Add(90 * time.Minute)}
+	second := astro.Interval{Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour)}
+
+	if got := Intervals([]astro.Interval{first}, time.UTC); got != "20:00–21:30" {
+		t.Errorf("Intervals of one period = %q, want 20:00–21:30", got)
+	}
+	if got := Intervals([]astro.Interval{first, second}, time.UTC); got != "20:00–21:30 +1" {
+		t.Errorf("Intervals of two periods = %q, want 20:00–21:30 +1", got)
+	}
+}
+
+func TestPlanetClear(t *testing.T) {
+	start := time.Date(2024, time.November, 5, 20, 0, 0, 0, time.UTC)
+	clear := []astro.Interval{{Start: start, End: start.Add(time.Hour)}}
+
+	tests := []struct {
+		name       string
+		observable bool
+		clear      []astro.Interval
+		forecasted bool
+		want       string
+	}{
+		{"too low", false, nil, true, Unknown},
+		{"not forecast", true, nil, false, "?"},
+		{"clouded out", true, nil, true, "planets.clouded"},
+		{"clear", true, clear, true, "20:00–21:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlanetClear(tt.observable, tt.clear, tt.forecasted, time.UTC); got != tt.want {
+				t.Errorf("PlanetClear = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	known := 40
+	if got := Percent(-1); got != Unknown {
+		t.Errorf("Percent(-1) = %q, want %q", got, Unknown)
+	}
+	if got := KnownPercent(nil); got != Unknown {
+		t.Errorf("KnownPercent(nil) = %q, want %q", got, Unknown)
+	}
+	if got := KnownPercent(&known); got != "40%" {
+		t.Errorf("KnownPercent(40) = %q, want 40%%", got)
+	}
+}
+
+func TestCloudLayers(t *testing.T) {
+	if got := CloudLayers(0, 50, 100); got != "·▄█ 0/50/100" {
+		t.Errorf("CloudLayers = %q, want ·▄█ 0/50/100", got)
+	}
+}
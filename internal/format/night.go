@@ -0,0 +1,155 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/i18n"
+	"driffaud.fr/odin/internal/util"
+)
+
+// Sun shows the sunset, dusk, dawn and sunrise times of the night in loc
+func Sun(sunset, dusk, dawn, sunrise time.Time, loc *time.Location) string {
+	return i18n.T("weather.sunset", map[string]any{
+		"Sunset":  Time(sunset, loc),
+		"Dusk":    Time(dusk, loc),
+		"Dawn":    Time(dawn, loc),
+		"Sunrise": Time(sunrise, loc),
+	})
+}
+
+// Moon shows the moon phase, illumination and rise and set times of the night in loc
+func Moon(emoji, phase string, illumination float64, moonrise, moonset time.Time, loc *time.Location) string {
+	return i18n.T("weather.moonphase", map[string]any{
+		"MoonEmoji":    emoji,
+		"Moonrise":     Time(moonrise, loc),
+		"Moonset":      Time(moonset, loc),
+		"Illumination": fmt.Sprintf("%.0f", illumination),
+		"PhaseName":    phase,
+	})
+}
+
+// Darkness tells how dark the night gets when the sun does not reach -18°, and nothing
+// on an ordinary night. The darkness is the name of an astro.Darkness.
+func Darkness(darkness string, darkestTime time.Time, darkestAltitude float64, polarNight bool, loc *time.Location) string {
+	data := map[string]any{
+		"Altitude": fmt.Sprintf("%.1f", darkestAltitude),
+		"Time":     Time(darkestTime, loc),
+	}
+
+	var lines []string
+	switch darkness {
+	case astro.DarknessAstronomical.String():
+	case astro.DarknessMidnightSun.String():
+		lines = append(lines, i18n.T("weather.midnight_sun", data))
+	default:
+		lines = append(lines, i18n.T("weather.no_astro_darkness", data))
+	}
+	if polarNight {
+		lines = append(lines, i18n.T("weather.polar_night", nil))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// DarkWindows lists the periods of fully dark sky with minute precision, or of the
+// darkest twilight when the sun does not reach -18°
+func DarkWindows(windows []astro.Interval, darkness string, loc *time.Location) string {
+	if len(windows) == 0 {
+		return i18n.T("weather.no_dark_window", nil)
+	}
+
+	periods := make([]string, len(windows))
+	for i, window := range windows {
+		periods[i] = Period(window.Start, window.End, loc)
+	}
+
+	if darkness != astro.DarknessAstronomical.String() {
+		return i18n.T("weather.darkest_window", map[string]any{
+			"Darkness": i18n.T("darkness."+darkness, nil),
+			"Windows":  strings.Join(periods, ", "),
+		})
+	}
+	return i18n.T("weather.dark_window", map[string]any{
+		"Windows": strings.Join(periods, ", "),
+	})
+}
+
+// Window describes an observation window and its average conditions and, when the
+// confidence is not negative, the probability from the ensemble that it actually happens
+func Window(start, end time.Time, hours int, cloudCover, seeing, rating float64, confidence int, loc *time.Location) string {
+	description := i18n.T("weather.window", map[string]any{
+		"Start":      Time(start, loc),
+		"End":        Time(end, loc),
+		"Hours":      hours,
+		"CloudCover": fmt.Sprintf("%.0f", cloudCover),
+		"Seeing":     fmt.Sprintf("%.1f", seeing),
+		"Rating":     fmt.Sprintf("%.1f", rating),
+	})
+	if confidence >= 0 {
+		description += " " + i18n.T("weather.confidence", map[string]any{"Confidence": confidence})
+	}
+	return description
+}
+
+// Conditions describes the average weather of the night
+func Conditions(units util.Units, temperature float64, humidity int, windSpeed float64, windDirection string, dewPoint float64) string {
+	return i18n.T("weather.conditions", map[string]any{
+		"Temp":      units.Temperature(temperature, 0),
+		"Humidity":  humidity,
+		"WindSpeed": units.WindSpeed(windSpeed, 0),
+		"WindDir":   windDirection,
+		"DewPoint":  units.Temperature(dewPoint, 0),
+	})
+}
+
+// PrecipitationAndSeeing shows the highest precipitation probability and the seeing of the night
+func PrecipitationAndSeeing(precipitation, seeing int) string {
+	return i18n.T("weather.precip_and_seeing", map[string]any{
+		"Precip": precipitation,
+		"Seeing": seeing,
+	})
+}
+
+// Dew describes the dew risk of the night, when dew starts to form and the suggested dew
+// heater power. The risk is the name of a forecast.DewRisk.
+func Dew(risk string, start time.Time, power int, loc *time.Location) string {
+	switch {
+	case risk == forecast.DewRiskNone.String():
+		return i18n.T("weather.no_dew", nil)
+	case start.IsZero():
+		return i18n.T("weather.dew_low", map[string]any{
+			"Risk":  i18n.T("dew_risk."+risk, nil),
+			"Power": power,
+		})
+	default:
+		return i18n.T("weather.dew", map[string]any{
+			"Risk":  i18n.T("dew_risk."+risk, nil),
+			"Start": Time(start, loc),
+			"Power": power,
+		})
+	}
+}
+
+// Gusts describes the strongest gusts of the night and the telescope shake risk.
+// The risk is the name of a forecast.ShakeRisk.
+func Gusts(units util.Units, gusts float64, risk string) string {
+	return i18n.T("weather.gusts", map[string]any{
+		"Gusts": units.WindSpeed(gusts, 0),
+		"Risk":  i18n.T("shake_risk."+risk, nil),
+	})
+}
+
+// Fog warns about fog forming during the night, unless start is zero
+func Fog(start time.Time, probability int, loc *time.Location) string {
+	if start.IsZero() {
+		return i18n.T("weather.no_fog", nil)
+	}
+	return i18n.T("weather.fog", map[string]any{
+		"Start":       Time(start, loc),
+		"Probability": probability,
+	})
+}
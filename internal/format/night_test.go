@@ -0,0 +1,39 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+)
+
+// Without translations, the i18n messages are their IDs
+func TestNightSummaryMessages(t *testing.T) {
+	start := time.Date(2024, time.November, 5, 23, 0, 0, 0, time.UTC)
+	windows := []astro.Interval{{Start: start, End: start.Add(time.Hour)}}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"no dew", Dew(forecast.DewRiskNone.String(), time.Time{}, 0, time.UTC), "weather.no_dew"},
+		{"dew without a start", Dew(forecast.DewRiskLow.String(), time.Time{}, 10, time.UTC), "weather.dew_low"},
+		{"dew", Dew(forecast.DewRiskHigh.String(), start, 80, time.UTC), "weather.dew"},
+		{"no fog", Fog(time.Time{}, 20, time.UTC), "weather.no_fog"},
+		{"fog", Fog(start, 80, time.UTC), "weather.fog"},
+		{"no dark window", DarkWindows(nil, astro.DarknessAstronomical.String(), time.UTC), "weather.no_dark_window"},
+		{"dark window", DarkWindows(windows, astro.DarknessAstronomical.String(), time.UTC), "weather.dark_window"},
+		{"darkest window", DarkWindows(windows, astro.DarknessNautical.String(), time.UTC), "weather.darkest_window"},
+		{"ordinary night", Darkness(astro.DarknessAstronomical.String(), start, -30, false, time.UTC), ""},
+		{"midnight sun", Darkness(astro.DarknessMidnightSun.String(), start, 2, false, time.UTC), "weather.midnight_sun"},
+		{"polar night", Darkness(astro.DarknessAstronomical.String(), start, -40, true, time.UTC), "weather.polar_night"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Xuanwo/go-locale"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -16,6 +18,7 @@ var localeFS embed.FS
 var (
 	bundle    *i18n.Bundle
 	localizer *i18n.Localizer
+	current   = language.English
 )
 
 var SupportedLocales = []language.Tag{
@@ -23,7 +26,9 @@ var SupportedLocales = []language.Tag{
 	language.English,
 }
 
-func Init() error {
+// Init loads the translations and selects the language. An empty language
+// uses the system locale, falling back to English when it cannot be detected.
+func Init(lang string) error {
 	bundle = i18n.NewBundle(language.English)
 	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
 
@@ -34,16 +39,33 @@ func Init() error {
 		}
 	}
 
-	tag, err := locale.Detect()
-	if err != nil {
-		return fmt.Errorf("failed to detect locale: %w", err)
+	if lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("invalid language %q: %w", lang, err)
+		}
+		matcher := language.NewMatcher(SupportedLocales)
+		if _, _, confidence := matcher.Match(tag); confidence == language.No {
+			return fmt.Errorf("unsupported language %q", lang)
+		}
+	} else if tag, err := locale.Detect(); err == nil {
+		lang = tag.String()
+	} else {
+		lang = language.English.String()
 	}
 
-	localizer = i18n.NewLocalizer(bundle, tag.String())
+	localizer = i18n.NewLocalizer(bundle, lang)
+	current, _, _ = language.NewMatcher(SupportedLocales).Match(language.Make(lang))
 
 	return nil
 }
 
+// Language returns the base language code of the selected locale, such as "en" or "fr"
+func Language() string {
+	base, _ := current.Base()
+	return base.String()
+}
+
 func T(messageID string, templateData map[string]any) string {
 	if localizer == nil {
 		return messageID
@@ -60,3 +82,8 @@ func T(messageID string, templateData map[string]any) string {
 
 	return msg
 }
+
+// Weekday returns the abbreviated name of a day of the week in the selected language
+func Weekday(day time.Weekday) string {
+	return T("weekday."+strings.ToLower(day.String()), nil)
+}
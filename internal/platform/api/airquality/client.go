@@ -0,0 +1,68 @@
+package airquality
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+const airQualityAPI = "https://air-quality-api.open-meteo.com/v1/air-quality"
+
+// MaxForecastDays is the longest forecast the air-quality API provides
+const MaxForecastDays = 7
+
+// Client fetches aerosol and particulate forecasts from an Open-Meteo compatible air-quality API
+type Client struct {
+	BaseURL      string
+	ForecastDays int
+	HTTPClient   *http.Client
+}
+
+// DefaultClient queries the public Open-Meteo air-quality API
+var DefaultClient = &Client{
+	BaseURL:      airQualityAPI,
+	ForecastDays: MaxForecastDays,
+	HTTPClient:   http.DefaultClient,
+}
+
+// GetAirQuality fetches the air-quality forecast for the given coordinates using the default client
+func GetAirQuality(lat, lon float64) (domain.AirQualityData, error) {
+	return DefaultClient.GetAirQuality(lat, lon)
+}
+
+// GetAirQuality fetches the air-quality forecast for the given coordinates
+func (c *Client) GetAirQuality(lat, lon float64) (domain.AirQualityData, error) {
+	var airQuality domain.AirQualityData
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return airQuality, fmt.Errorf("invalid air-quality API URL: %w", err)
+	}
+	params := url.Values{}
+	params.Add("latitude", fmt.Sprintf("%f", lat))
+	params.Add("longitude", fmt.Sprintf("%f", lon))
+	params.Add("hourly", "aerosol_optical_depth,dust,pm2_5,pm10")
+	params.Add("timezone", "auto")
+	params.Add("forecast_days", strconv.Itoa(c.ForecastDays))
+	baseURL.RawQuery = params.Encode()
+
+	resp, err := c.HTTPClient.Get(baseURL.String())
+	if err != nil {
+		return airQuality, fmt.Errorf("failed to fetch air-quality data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return airQuality, fmt.Errorf("air-quality API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&airQuality); err != nil {
+		return airQuality, fmt.Errorf("failed to decode air-quality data: %w", err)
+	}
+
+	return airQuality, nil
+}
@@ -0,0 +1,65 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+const archiveAPI = "https://archive-api.open-meteo.com/v1/archive"
+
+// Client fetches the observed weather from an Open-Meteo compatible historical weather API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// DefaultClient queries the public Open-Meteo historical weather API
+var DefaultClient = &Client{
+	BaseURL:    archiveAPI,
+	HTTPClient: http.DefaultClient,
+}
+
+// GetHistoricalWeather fetches the observed hourly weather between two dates using the default client
+func GetHistoricalWeather(lat, lon float64, start, end time.Time) (domain.HistoricalWeather, error) {
+	return DefaultClient.GetHistoricalWeather(lat, lon, start, end)
+}
+
+// GetHistoricalWeather fetches the observed hourly weather between two dates, both included.
+// The reanalysis lags a few days behind, so the most recent hours may have no data yet.
+func (c *Client) GetHistoricalWeather(lat, lon float64, start, end time.Time) (domain.HistoricalWeather, error) {
+	var weather domain.HistoricalWeather
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return weather, fmt.Errorf("invalid archive API URL: %w", err)
+	}
+	params := url.Values{}
+	params.Add("latitude", fmt.Sprintf("%f", lat))
+	params.Add("longitude", fmt.Sprintf("%f", lon))
+	params.Add("hourly", "cloud_cover,temperature_2m")
+	params.Add("timezone", "auto")
+	params.Add("start_date", start.Format(time.DateOnly))
+	params.Add("end_date", end.Format(time.DateOnly))
+	baseURL.RawQuery = params.Encode()
+
+	resp, err := c.HTTPClient.Get(baseURL.String())
+	if err != nil {
+		return weather, fmt.Errorf("failed to fetch historical weather: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return weather, fmt.Errorf("archive API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&weather); err != nil {
+		return weather, fmt.Errorf("failed to decode historical weather: %w", err)
+	}
+
+	return weather, nil
+}
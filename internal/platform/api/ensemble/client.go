@@ -0,0 +1,118 @@
+package ensemble
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+const ensembleAPI = "https://ensemble-api.open-meteo.com/v1/ensemble"
+
+// cloudCoverVariable is the hourly variable requested from every member. The control run is
+// returned under this name and the perturbed members as cloud_cover_member01, 02...
+const cloudCoverVariable = "cloud_cover"
+
+// Client fetches ensemble forecasts from an Open-Meteo compatible ensemble API
+type Client struct {
+	BaseURL      string
+	ForecastDays int
+	Model        string
+	HTTPClient   *http.Client
+}
+
+// DefaultClient queries the public Open-Meteo ensemble API
+var DefaultClient = &Client{
+	BaseURL:      ensembleAPI,
+	ForecastDays: 7,
+	Model:        "icon_seamless",
+	HTTPClient:   http.DefaultClient,
+}
+
+// ensembleResponse is the raw API response, whose member series have numbered keys
+type ensembleResponse struct {
+	Hourly map[string]json.RawMessage `json:"hourly"`
+}
+
+// GetCloudCover fetches the cloud cover of every ensemble member for the given coordinates
+// using the default client
+func GetCloudCover(lat, lon float64) (domain.EnsembleData, error) {
+	return DefaultClient.GetCloudCover(lat, lon)
+}
+
+// GetCloudCover fetches the cloud cover of every ensemble member for the given coordinates
+func (c *Client) GetCloudCover(lat, lon float64) (domain.EnsembleData, error) {
+	ensemble := domain.EnsembleData{Model: c.Model}
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ensemble, fmt.Errorf("invalid ensemble API URL: %w", err)
+	}
+	params := url.Values{}
+	params.Add("latitude", fmt.Sprintf("%f", lat))
+	params.Add("longitude", fmt.Sprintf("%f", lon))
+	params.Add("hourly", cloudCoverVariable)
+	params.Add("timezone", "auto")
+	params.Add("forecast_days", strconv.Itoa(c.ForecastDays))
+	params.Add("models", c.Model)
+	baseURL.RawQuery = params.Encode()
+
+	resp, err := c.HTTPClient.Get(baseURL.String())
+	if err != nil {
+		return ensemble, fmt.Errorf("failed to fetch ensemble data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ensemble, fmt.Errorf("ensemble API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	var raw ensembleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return ensemble, fmt.Errorf("failed to decode ensemble data: %w", err)
+	}
+
+	return decodeMembers(raw, ensemble)
+}
+
+// decodeMembers collects the time axis and the cloud cover series of every member
+func decodeMembers(raw ensembleResponse, ensemble domain.EnsembleData) (domain.EnsembleData, error) {
+	if err := json.Unmarshal(raw.Hourly["time"], &ensemble.Time); err != nil {
+		return ensemble, fmt.Errorf("failed to decode ensemble time: %w", err)
+	}
+
+	var keys []string
+	for key := range raw.Hourly {
+		if strings.HasPrefix(key, cloudCoverVariable) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var values []*float64
+		if err := json.Unmarshal(raw.Hourly[key], &values); err != nil {
+			return ensemble, fmt.Errorf("failed to decode ensemble %s: %w", key, err)
+		}
+
+		member := make([]float64, len(values))
+		for i, value := range values {
+			member[i] = -1
+			if value != nil {
+				member[i] = *value
+			}
+		}
+		ensemble.CloudCover = append(ensemble.CloudCover, member)
+	}
+
+	if len(ensemble.CloudCover) == 0 {
+		return ensemble, fmt.Errorf("ensemble model %s returned no cloud cover", ensemble.Model)
+	}
+
+	return ensemble, nil
+}
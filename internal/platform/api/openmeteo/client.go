@@ -3,31 +3,112 @@ package openmeteo
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/api/airquality"
+	"driffaud.fr/odin/internal/platform/api/ensemble"
 )
 
 const openMeteoAPI = "https://api.open-meteo.com/v1/forecast"
 
+// Client fetches forecasts from an Open-Meteo compatible API
+type Client struct {
+	BaseURL      string
+	ForecastDays int
+	Models       string
+	HTTPClient   *http.Client
+
+	// ComparisonModels are the weather models compared side by side by GetModelComparison
+	ComparisonModels []string
+
+	// AirQuality, when set, adds the aerosol forecast used for the transparency estimate
+	AirQuality *airquality.Client
+	// Ensemble, when set, adds the ensemble cloud cover used for the clear-sky probability
+	Ensemble *ensemble.Client
+	// Recorder, when set, receives every fetched forecast
+	Recorder ForecastRecorder
+}
+
+// ForecastRecorder receives the forecasts fetched by a client, for instance to verify them later
+type ForecastRecorder interface {
+	RecordWeather(lat, lon float64, model string, weather domain.WeatherData)
+	RecordModels(lat, lon float64, comparison domain.ModelComparison)
+}
+
+// DefaultClient queries the public Open-Meteo API
+var DefaultClient = &Client{
+	BaseURL:      openMeteoAPI,
+	ForecastDays: 7,
+	Models:       "best_match",
+	HTTPClient:   http.DefaultClient,
+	AirQuality:   airquality.DefaultClient,
+	Ensemble:     ensemble.DefaultClient,
+
+	ComparisonModels: DefaultComparisonModels,
+}
+
+// WithRecorder returns a copy of the client that passes every fetched forecast to recorder
+func (c *Client) WithRecorder(recorder ForecastRecorder) *Client {
+	recording := *c
+	recording.Recorder = recorder
+	return &recording
+}
+
+// DefaultComparisonModels are ECMWF IFS, GFS, ICON, ARPEGE/AROME and MET Nordic
+var DefaultComparisonModels = []string{"ecmwf_ifs025", "gfs_seamless", "icon_seamless", "meteofrance_seamless", "metno_seamless"}
+
+// pressureLevels are the pressure levels in hPa requested for the upper-air seeing model
+var pressureLevels = []int{850, 700, 500, 300, 250, 200}
+
+// humidityLevels are the mid-level pressure levels in hPa whose humidity feeds the transparency estimate
+var humidityLevels = []int{700, 500}
+
+// pressureLevelVariables returns the hourly wind speed and temperature variables of every pressure level
+// and the relative humidity variables of the mid levels
+func pressureLevelVariables() string {
+	variables := make([]string, 0, 2*len(pressureLevels)+len(humidityLevels))
+	for _, level := range pressureLevels {
+		variables = append(variables, fmt.Sprintf("wind_speed_%dhPa", level), fmt.Sprintf("temperature_%dhPa", level))
+	}
+	for _, level := range humidityLevels {
+		variables = append(variables, fmt.Sprintf("relative_humidity_%dhPa", level))
+	}
+	return strings.Join(variables, ",")
+}
+
+// GetWeather fetches the forecast for the given coordinates using the default client
 func GetWeather(lat, lon float64) (domain.WeatherData, error) {
-	baseURL, _ := url.Parse(openMeteoAPI)
+	return DefaultClient.GetWeather(lat, lon)
+}
+
+// GetWeather fetches the forecast for the given coordinates. The air-quality and ensemble
+// forecasts are optional: when they cannot be fetched, the weather is returned without them.
+func (c *Client) GetWeather(lat, lon float64) (domain.WeatherData, error) {
+	var weather domain.WeatherData
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return weather, fmt.Errorf("invalid openmeteo API URL: %w", err)
+	}
 	params := url.Values{}
 	params.Add("latitude", fmt.Sprintf("%f", lat))
 	params.Add("longitude", fmt.Sprintf("%f", lon))
 	params.Add("current", "temperature_2m,relative_humidity_2m,cloud_cover,wind_speed_10m,wind_direction_10m,precipitation_probability,dew_point_2m")
-	params.Add("hourly", "precipitation_probability,dew_point_2m,temperature_2m,relative_humidity_2m,cloud_cover,cloud_cover_low,cloud_cover_mid,cloud_cover_high,wind_speed_10m,wind_direction_10m")
+	params.Add("hourly", "precipitation_probability,dew_point_2m,temperature_2m,relative_humidity_2m,cloud_cover,cloud_cover_low,cloud_cover_mid,cloud_cover_high,wind_speed_10m,wind_direction_10m,wind_gusts_10m,visibility,"+pressureLevelVariables())
 	params.Add("daily", "sunrise,sunset")
 	params.Add("timezone", "auto")
-	params.Add("forecast_days", "7")
-	params.Add("models", "best_match")
+	params.Add("forecast_days", strconv.Itoa(c.ForecastDays))
+	params.Add("models", c.Models)
 	baseURL.RawQuery = params.Encode()
 	url := baseURL.String()
 
-	var weather domain.WeatherData
-
-	resp, err := http.Get(url)
+	resp, err := c.HTTPClient.Get(url)
 	if err != nil {
 		return weather, fmt.Errorf("failed to fetch weather data: %w", err)
 	}
@@ -41,5 +122,142 @@ func GetWeather(lat, lon float64) (domain.WeatherData, error) {
 		return weather, fmt.Errorf("failed to decode weather data: %w", err)
 	}
 
+	if c.AirQuality != nil {
+		if airQuality, err := c.AirQuality.GetAirQuality(lat, lon); err == nil {
+			weather.AirQuality = &airQuality
+		}
+	}
+	if c.Ensemble != nil {
+		if members, err := c.Ensemble.GetCloudCover(lat, lon); err == nil {
+			weather.Ensemble = &members
+		}
+	}
+	if c.Recorder != nil {
+		c.Recorder.RecordWeather(lat, lon, c.Models, weather)
+	}
+
 	return weather, nil
 }
+
+// GetLocation looks up the time zone of the given coordinates using the default client
+func GetLocation(lat, lon float64) (*time.Location, error) {
+	return DefaultClient.GetLocation(lat, lon)
+}
+
+// GetLocation looks up the time zone of the given coordinates, without fetching a forecast
+func (c *Client) GetLocation(lat, lon float64) (*time.Location, error) {
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid openmeteo API URL: %w", err)
+	}
+	params := url.Values{}
+	params.Add("latitude", fmt.Sprintf("%f", lat))
+	params.Add("longitude", fmt.Sprintf("%f", lon))
+	params.Add("timezone", "auto")
+	params.Add("forecast_days", "1")
+	baseURL.RawQuery = params.Encode()
+
+	resp, err := c.HTTPClient.Get(baseURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch time zone: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openmeteo API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	var site domain.WeatherData
+	if err := json.NewDecoder(resp.Body).Decode(&site); err != nil {
+		return nil, fmt.Errorf("failed to decode time zone: %w", err)
+	}
+	return site.Location(), nil
+}
+
+// comparisonResponse is the raw response of a multi-model request, whose series are
+// suffixed with the model name
+type comparisonResponse struct {
+	Timezone  string                     `json:"timezone"`
+	UTCOffset int                        `json:"utc_offset_seconds"`
+	Hourly    map[string]json.RawMessage `json:"hourly"`
+}
+
+// GetModelComparison fetches the cloud cover of every comparison model for the given
+// coordinates using the default client
+func GetModelComparison(lat, lon float64) (domain.ModelComparison, error) {
+	return DefaultClient.GetModelComparison(lat, lon)
+}
+
+// GetModelComparison fetches the cloud cover of every comparison model for the given coordinates
+// in a single request
+func (c *Client) GetModelComparison(lat, lon float64) (domain.ModelComparison, error) {
+	var comparison domain.ModelComparison
+
+	if len(c.ComparisonModels) == 0 {
+		return comparison, fmt.Errorf("no weather models to compare")
+	}
+
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return comparison, fmt.Errorf("invalid openmeteo API URL: %w", err)
+	}
+	params := url.Values{}
+	params.Add("latitude", fmt.Sprintf("%f", lat))
+	params.Add("longitude", fmt.Sprintf("%f", lon))
+	params.Add("hourly", "cloud_cover")
+	params.Add("timezone", "auto")
+	params.Add("forecast_days", strconv.Itoa(c.ForecastDays))
+	params.Add("models", strings.Join(c.ComparisonModels, ","))
+	baseURL.RawQuery = params.Encode()
+
+	resp, err := c.HTTPClient.Get(baseURL.String())
+	if err != nil {
+		return comparison, fmt.Errorf("failed to fetch model comparison: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return comparison, fmt.Errorf("openmeteo API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	var raw comparisonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return comparison, fmt.Errorf("failed to decode model comparison: %w", err)
+	}
+
+	comparison.Timezone = raw.Timezone
+	comparison.UTCOffset = raw.UTCOffset
+	if err := json.Unmarshal(raw.Hourly["time"], &comparison.Time); err != nil {
+		return comparison, fmt.Errorf("failed to decode model comparison time: %w", err)
+	}
+
+	for _, model := range c.ComparisonModels {
+		// A single model is returned without the suffix
+		key := "cloud_cover_" + model
+		if len(c.ComparisonModels) == 1 {
+			key = "cloud_cover"
+		}
+
+		series := domain.ModelSeries{Model: model}
+		var values []*float64
+		if data, ok := raw.Hourly[key]; ok {
+			if err := json.Unmarshal(data, &values); err != nil {
+				return comparison, fmt.Errorf("failed to decode %s cloud cover: %w", model, err)
+			}
+		}
+		series.CloudCover = make([]int, len(comparison.Time))
+		for i := range series.CloudCover {
+			series.CloudCover[i] = -1
+			if i < len(values) && values[i] != nil {
+				series.CloudCover[i] = int(math.Round(*values[i]))
+			}
+		}
+		comparison.Models = append(comparison.Models, series)
+	}
+
+	if c.Recorder != nil {
+		c.Recorder.RecordModels(lat, lon, comparison)
+	}
+
+	return comparison, nil
+}
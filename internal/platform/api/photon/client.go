@@ -29,14 +29,35 @@ type PhotonResponse struct {
 	} `json:"features"`
 }
 
-// SearchPlaces searches for places based on the provided query
+// Client searches places with a Photon compatible API
+type Client struct {
+	BaseURL    string
+	Language   string
+	HTTPClient *http.Client
+}
+
+// DefaultClient queries the public Photon API
+var DefaultClient = &Client{
+	BaseURL:    photonAPI,
+	Language:   "fr",
+	HTTPClient: http.DefaultClient,
+}
+
+// SearchPlaces searches for places based on the provided query using the default client
 func SearchPlaces(query string) ([]domain.Place, error) {
+	return DefaultClient.SearchPlaces(query)
+}
+
+// SearchPlaces searches for places based on the provided query
+func (c *Client) SearchPlaces(query string) ([]domain.Place, error) {
 	params := url.Values{}
 	params.Add("q", query)
-	params.Add("lang", "fr")
-	reqURL := photonAPI + "?" + params.Encode()
+	if c.Language != "" {
+		params.Add("lang", c.Language)
+	}
+	reqURL := c.BaseURL + "?" + params.Encode()
 
-	resp, err := http.Get(reqURL)
+	resp, err := c.HTTPClient.Get(reqURL)
 	if err != nil {
 		return nil, fmt.Errorf("photon API request failed: %w", err)
 	}
@@ -3,8 +3,10 @@ package storage
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"driffaud.fr/odin/internal/domain"
 )
@@ -15,15 +17,25 @@ type FavoritesStore struct {
 	FilePath  string
 }
 
-// NewFavoritesStore creates a new store for favorite places
-func NewFavoritesStore() (*FavoritesStore, error) {
+// AppConfigDir returns the directory holding the application files, creating it if needed
+func AppConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	appConfigDir := configDir + "/odin"
 	if err := os.MkdirAll(appConfigDir, 0755); err != nil {
+		return "", err
+	}
+
+	return appConfigDir, nil
+}
+
+// NewFavoritesStore creates a new store for favorite places
+func NewFavoritesStore() (*FavoritesStore, error) {
+	appConfigDir, err := AppConfigDir()
+	if err != nil {
 		return nil, err
 	}
 
@@ -94,3 +106,42 @@ func (fs *FavoritesStore) IsFavorite(place domain.Place) bool {
 	}
 	return false
 }
+
+// FindFavorite looks up a favorite by name, ignoring case
+func (fs *FavoritesStore) FindFavorite(name string) (domain.Place, bool) {
+	for _, fav := range fs.Favorites {
+		if strings.EqualFold(fav.Name, name) {
+			return fav, true
+		}
+	}
+	return domain.Place{}, false
+}
+
+// RenameFavorite changes the name of the favorite called oldName
+func (fs *FavoritesStore) RenameFavorite(oldName, newName string) error {
+	if _, exists := fs.FindFavorite(newName); exists && !strings.EqualFold(oldName, newName) {
+		return fmt.Errorf("a favorite named '%s' already exists", newName)
+	}
+
+	for i, fav := range fs.Favorites {
+		if strings.EqualFold(fav.Name, oldName) {
+			fs.Favorites[i].Name = newName
+			return fs.Save()
+		}
+	}
+
+	return fmt.Errorf("no favorite named '%s'", oldName)
+}
+
+// SetFavoriteProfile changes the scoring profile of the favorite called name.
+// An empty profile falls back to the configured one.
+func (fs *FavoritesStore) SetFavoriteProfile(name, profile string) error {
+	for i, fav := range fs.Favorites {
+		if strings.EqualFold(fav.Name, name) {
+			fs.Favorites[i].Profile = profile
+			return fs.Save()
+		}
+	}
+
+	return fmt.Errorf("no favorite named '%s'", name)
+}
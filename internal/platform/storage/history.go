@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+// HistoryRetention is how long fetched forecasts are kept for verification
+const HistoryRetention = 30 * 24 * time.Hour
+
+// MaxHistoryRecords is the number of forecasts kept at most, the oldest being dropped first
+const MaxHistoryRecords = 2000
+
+// HistoryStore manages the forecasts kept to verify them against the observed weather
+type HistoryStore struct {
+	Records  []domain.ForecastRecord
+	FilePath string
+}
+
+// NewHistoryStore creates a new store for the forecast history
+func NewHistoryStore() (*HistoryStore, error) {
+	appConfigDir, err := AppConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &HistoryStore{
+		FilePath: filepath.Join(appConfigDir, "history.json"),
+	}
+
+	if err := store.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Load reads the forecast history from the JSON file
+func (s *HistoryStore) Load() error {
+	data, err := os.ReadFile(s.FilePath)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, &s.Records)
+}
+
+// Save stores the forecast history to the JSON file. The file is replaced at once so that
+// an interrupted write never leaves a truncated history behind.
+func (s *HistoryStore) Save() error {
+	data, err := json.Marshal(s.Records)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.FilePath), filepath.Base(s.FilePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Chmod(0644); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.FilePath)
+}
+
+// historyCoordinateScale rounds the coordinates of the records to about 10 m, so that
+// a favorite keeps its history when it is renamed and two sites never share one
+const historyCoordinateScale = 1e4
+
+// samePlace reports whether two records were fetched for the same coordinates
+func samePlace(lat1, lon1, lat2, lon2 float64) bool {
+	return math.Round(lat1*historyCoordinateScale) == math.Round(lat2*historyCoordinateScale) &&
+		math.Round(lon1*historyCoordinateScale) == math.Round(lon2*historyCoordinateScale)
+}
+
+// AddRecords adds fetched forecasts and saves the history once. Each replaces the record
+// of the same coordinates and model fetched the same day. Records older than HistoryRetention
+// are dropped and at most MaxHistoryRecords of the most recent ones are kept.
+func (s *HistoryStore) AddRecords(added ...domain.ForecastRecord) error {
+	if len(added) == 0 {
+		return nil
+	}
+
+	records := s.Records
+	for _, record := range added {
+		records = replaceRecord(records, record)
+	}
+
+	if len(records) > MaxHistoryRecords {
+		sort.SliceStable(records, func(i, j int) bool {
+			return records[i].FetchedAt.Before(records[j].FetchedAt)
+		})
+		records = records[len(records)-MaxHistoryRecords:]
+	}
+
+	s.Records = records
+	return s.Save()
+}
+
+// replaceRecord appends record to records, leaving out the one it replaces and those past
+// the retention
+func replaceRecord(records []domain.ForecastRecord, record domain.ForecastRecord) []domain.ForecastRecord {
+	fetchedDay := record.FetchedAt.UTC().Truncate(24 * time.Hour)
+	cutoff := record.FetchedAt.Add(-HistoryRetention)
+
+	kept := []domain.ForecastRecord{}
+	for _, existing := range records {
+		if existing.FetchedAt.Before(cutoff) {
+			continue
+		}
+		if samePlace(existing.Latitude, existing.Longitude, record.Latitude, record.Longitude) && existing.Model == record.Model &&
+			existing.FetchedAt.UTC().Truncate(24*time.Hour).Equal(fetchedDay) {
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	return append(kept, record)
+}
+
+// PlaceRecords returns the records fetched for the given coordinates, whatever the name
+// of the place at the time
+func (s *HistoryStore) PlaceRecords(lat, lon float64) []domain.ForecastRecord {
+	var records []domain.ForecastRecord
+	for _, record := range s.Records {
+		if samePlace(record.Latitude, record.Longitude, lat, lon) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+func newTestHistory(t *testing.T) *HistoryStore {
+	t.Helper()
+	return &HistoryStore{FilePath: filepath.Join(t.TempDir(), "history.json")}
+}
+
+const homeLat, homeLon = 45.1885, 5.7245
+
+func record(place string, lat, lon float64, model string, fetchedAt time.Time) domain.ForecastRecord {
+	return domain.ForecastRecord{
+		Place:      place,
+		Latitude:   lat,
+		Longitude:  lon,
+		Model:      model,
+		FetchedAt:  fetchedAt,
+		Time:       []string{fetchedAt.Format("2006-01-02T15:04")},
+		CloudCover: []int{10},
+	}
+}
+
+func TestAddRecords(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+	home := record("Home", homeLat, homeLon, "best_match", now)
+
+	tests := []struct {
+		name     string
+		existing []domain.ForecastRecord
+		added    []domain.ForecastRecord
+		want     int
+	}{
+		{
+			name:  "first record",
+			added: []domain.ForecastRecord{home},
+			want:  1,
+		},
+		{
+			name:     "same place, model and day",
+			existing: []domain.ForecastRecord{record("Home", homeLat, homeLon, "best_match", now.Add(-10*time.Hour))},
+			added:    []domain.ForecastRecord{home},
+			want:     1,
+		},
+		{
+			name:     "renamed place",
+			existing: []domain.ForecastRecord{record("Garden", homeLat+1e-7, homeLon, "best_match", now.Add(-time.Hour))},
+			added:    []domain.ForecastRecord{home},
+			want:     1,
+		},
+		{
+			name:     "previous day",
+			existing: []domain.ForecastRecord{record("Home", homeLat, homeLon, "best_match", now.Add(-24*time.Hour))},
+			added:    []domain.ForecastRecord{home},
+			want:     2,
+		},
+		{
+			name:     "other model",
+			existing: []domain.ForecastRecord{record("Home", homeLat, homeLon, "gfs_seamless", now)},
+			added:    []domain.ForecastRecord{home},
+			want:     2,
+		},
+		{
+			name:     "other place with the same name",
+			existing: []domain.ForecastRecord{record("Home", homeLat+0.001, homeLon, "best_match", now)},
+			added:    []domain.ForecastRecord{home},
+			want:     2,
+		},
+		{
+			name:     "past retention",
+			existing: []domain.ForecastRecord{record("Home", homeLat, homeLon, "best_match", now.Add(-HistoryRetention-time.Hour))},
+			added:    []domain.ForecastRecord{home},
+			want:     1,
+		},
+		{
+			name:  "several models",
+			added: []domain.ForecastRecord{record("Home", homeLat, homeLon, "gfs_seamless", now), home},
+			want:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestHistory(t)
+			store.Records = tt.existing
+			if err := store.AddRecords(tt.added...); err != nil {
+				t.Fatalf("AddRecords failed: %v", err)
+			}
+			if len(store.Records) != tt.want {
+				t.Errorf("len(Records) = %d, want %d", len(store.Records), tt.want)
+			}
+			if last := store.Records[len(store.Records)-1]; !last.FetchedAt.Equal(home.FetchedAt) || last.Place != home.Place {
+				t.Errorf("last record = %s at %v, want the added one", last.Place, last.FetchedAt)
+			}
+		})
+	}
+}
+
+func TestAddRecordsKeepsTheMostRecent(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+
+	store := newTestHistory(t)
+	for i := range MaxHistoryRecords {
+		place := fmt.Sprintf("Place %d", i)
+		store.Records = append(store.Records, record(place, float64(i)/100, 0, "best_match", now.Add(-time.Duration(i)*time.Minute)))
+	}
+	oldest := store.Records[MaxHistoryRecords-1]
+
+	if err := store.AddRecords(record("Home", homeLat, homeLon, "best_match", now)); err != nil {
+		t.Fatalf("AddRecords failed: %v", err)
+	}
+	if len(store.Records) != MaxHistoryRecords {
+		t.Fatalf("len(Records) = %d, want %d", len(store.Records), MaxHistoryRecords)
+	}
+	for _, r := range store.Records {
+		if r.Place == oldest.Place {
+			t.Errorf("oldest record of %s kept, want it dropped", r.Place)
+		}
+	}
+}
+
+func TestHistorySaveLoad(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 0, 0, 0, time.UTC)
+
+	store := newTestHistory(t)
+	if err := os.WriteFile(store.FilePath, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddRecords(record("Home", homeLat, homeLon, "best_match", now)); err != nil {
+		t.Fatalf("AddRecords failed: %v", err)
+	}
+
+	loaded := &HistoryStore{FilePath: store.FilePath}
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if records := loaded.PlaceRecords(homeLat, homeLon); len(records) != 1 || !records[0].FetchedAt.Equal(now) {
+		t.Errorf("PlaceRecords = %+v, want the saved record", records)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(store.FilePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want history.json alone", len(entries))
+	}
+}
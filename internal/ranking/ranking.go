@@ -0,0 +1,96 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/util"
+)
+
+// Entry holds the analysis of a night for a single place
+type Entry struct {
+	Place   domain.Place
+	Profile forecast.Profile
+	Night   forecast.NightForecast
+	Err     error
+}
+
+// BestWindowHours returns the length in hours of the best observation window, or 0 if there is none
+func (e Entry) BestWindowHours() int {
+	window, ok := e.Night.BestWindow()
+	if e.Err != nil || !ok {
+		return 0
+	}
+	return window.Hours()
+}
+
+// RankPlaces fetches the forecast of every place concurrently with client, analyzes the night
+// of date for each of them and returns the entries sorted from best to worst. Every place is
+// analyzed with the same profile, whatever the profile of the favorite, so that their windows,
+// cloud cover and seeing are held to the same thresholds. The date is read in the time zone of
+// each place (tonight if empty, see util.ParseNightDate); a place where that night is already
+// past fails with util.ErrPastDate.
+func RankPlaces(client *openmeteo.Client, places []domain.Place, date string, profile forecast.Profile) []Entry {
+	entries := make([]Entry, len(places))
+
+	var wg sync.WaitGroup
+	for i, place := range places {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries[i] = analyzePlace(client, place, date, profile)
+		}()
+	}
+	wg.Wait()
+
+	Sort(entries)
+	return entries
+}
+
+// Sort orders entries by best window length, then cloud cover, then seeing index, which only
+// compares nights analyzed with the same profile. Entries that failed to load are placed last.
+func Sort(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.BestWindowHours() != b.BestWindowHours() {
+			return a.BestWindowHours() > b.BestWindowHours()
+		}
+		if a.Night.DisplayCloudCover != b.Night.DisplayCloudCover {
+			return a.Night.DisplayCloudCover < b.Night.DisplayCloudCover
+		}
+		return a.Night.SeeingIndex > b.Night.SeeingIndex
+	})
+}
+
+func analyzePlace(client *openmeteo.Client, place domain.Place, date string, profile forecast.Profile) Entry {
+	weather, err := client.GetWeather(place.Latitude, place.Longitude)
+	if err != nil {
+		return Entry{Place: place, Profile: profile, Err: err}
+	}
+
+	forecastData := forecast.GenerateForecastDataWithProfile(weather, profile)
+	nightDate, err := util.ParseNightDate(date, weather.Location(), time.Now())
+	if err != nil {
+		return Entry{Place: place, Profile: profile, Err: err}
+	}
+	at := astro.ObserverTime(nightDate, weather.Location(), time.Now())
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, at)
+	if !forecast.CoversNight(forecastData, sunInfo) {
+		return Entry{Place: place, Profile: profile, Err: fmt.Errorf("no forecast yet for the night of %s", at.Format(time.DateOnly))}
+	}
+
+	return Entry{
+		Place:   place,
+		Profile: profile,
+		Night:   forecast.AnalyzeNight(forecastData, place.Latitude, place.Longitude, sunInfo, profile.Thresholds),
+	}
+}
@@ -0,0 +1,578 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/domain/deepsky"
+	"driffaud.fr/odin/internal/domain/planets"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/verification"
+)
+
+// Forecast is the JSON representation of a complete forecast for a place.
+// Field names carry their unit so the output can be consumed without documentation.
+type Forecast struct {
+	Place      Place          `json:"place"`
+	Timezone   string         `json:"timezone"`
+	Profile    string         `json:"profile"`
+	Date       string         `json:"date"`       // day on which the analyzed night starts
+	Forecasted bool           `json:"forecasted"` // false when the night is beyond the forecast horizon
+	Sun        Sun            `json:"sun"`
+	Moon       Moon           `json:"moon"`
+	Night      Night          `json:"night"`
+	Outlook    []OutlookNight `json:"outlook"`
+	Planets    []Planet       `json:"planets"`
+	Targets    []Target       `json:"targets"`
+	Hours      []Hour         `json:"hours"`
+}
+
+// Planet is the JSON representation of the visibility of a planet during the night
+type Planet struct {
+	Name            string     `json:"name"`
+	Rise            *time.Time `json:"rise"`
+	Transit         *time.Time `json:"transit"`
+	TransitAltitude float64    `json:"transit_altitude_degrees"`
+	Set             *time.Time `json:"set"`
+	MaxAltitude     float64    `json:"max_altitude_degrees"`
+	Magnitude       float64    `json:"magnitude"`
+	Diameter        float64    `json:"diameter_arcsec"`
+	Elongation      float64    `json:"elongation_degrees"` // positive east of the sun, in the evening sky
+	Observable      []Interval `json:"observable"`
+	Clear           []Interval `json:"clear"` // observable within an observation window, null when the night is not forecast
+}
+
+// Target is the JSON representation of a deep-sky object suggested for the night
+type Target struct {
+	ID              string     `json:"id"`
+	Designation     string     `json:"designation,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	Type            string     `json:"type"`
+	Constellation   string     `json:"constellation"`
+	RightAscension  float64    `json:"right_ascension_degrees"`
+	Declination     float64    `json:"declination_degrees"`
+	Magnitude       *float64   `json:"magnitude"`
+	Size            float64    `json:"size_arcmin"`
+	Up              []Interval `json:"up"` // above the profile's target altitude within the clear windows, or the dark windows when the night is not forecast
+	Hours           float64    `json:"hours_up"`
+	Transit         time.Time  `json:"transit"`
+	TransitAltitude float64    `json:"transit_altitude_degrees"`
+	MoonSeparation  float64    `json:"moon_separation_degrees"`
+	MoonUp          bool       `json:"moon_up"`
+	Score           float64    `json:"score"`
+}
+
+// Place is the JSON representation of a place
+type Place struct {
+	Name      string  `json:"name"`
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Profile   string  `json:"profile,omitempty"`
+}
+
+// Sun is the JSON representation of the sun times for tonight.
+// Times the sun does not reach, at high latitudes, are null and the darkness tells why.
+type Sun struct {
+	Sunset          *time.Time `json:"sunset"`
+	Dusk            *time.Time `json:"dusk"`
+	Dawn            *time.Time `json:"dawn"`
+	Sunrise         *time.Time `json:"sunrise"`
+	Darkness        string     `json:"darkness"`
+	DarkestTime     time.Time  `json:"darkest_time"`
+	DarkestAltitude float64    `json:"darkest_sun_altitude_degrees"`
+	DarkestBand     *Interval  `json:"darkest_band,omitempty"`
+	PolarNight      bool       `json:"polar_night"`
+}
+
+// Moon is the JSON representation of the moon phase and times for tonight.
+// The moonrise or moonset is null when the moon does not rise or set that night.
+type Moon struct {
+	Phase        string     `json:"phase"`
+	Emoji        string     `json:"emoji"`
+	Illumination float64    `json:"illumination_percent"`
+	Moonrise     *time.Time `json:"moonrise"`
+	Moonset      *time.Time `json:"moonset"`
+}
+
+// Window is the JSON representation of an observation window
+type Window struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Hours      int       `json:"hours"`
+	CloudCover int       `json:"cloud_cover_percent"`
+	Seeing     float64   `json:"seeing"`
+	Rating     float64   `json:"rating"`
+	Confidence *int      `json:"confidence_percent,omitempty"`
+}
+
+// Interval is the JSON representation of a span of time
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Night is the JSON representation of a night forecast analysis
+type Night struct {
+	DarkWindows              []Interval `json:"dark_windows"`
+	BestPeriod               *Window    `json:"best_period"`
+	Windows                  []Window   `json:"windows"`
+	CloudCover               int        `json:"cloud_cover_percent"`
+	MaxCloudCover            int        `json:"max_cloud_cover_percent"`
+	Temperature              int        `json:"temperature_celsius"`
+	Humidity                 int        `json:"humidity_percent"`
+	WindSpeed                int        `json:"wind_speed_kmh"`
+	WindDirection            int        `json:"wind_direction_degrees"`
+	WindDirectionText        string     `json:"wind_direction"`
+	MaxWindGusts             int        `json:"max_wind_gusts_kmh"`
+	ShakeRisk                string     `json:"shake_risk"`
+	DewPoint                 int        `json:"dew_point_celsius"`
+	PrecipitationProbability int        `json:"precipitation_probability_percent"`
+	Seeing                   int        `json:"seeing"`
+	Transparency             int        `json:"transparency"`
+	Rating                   int        `json:"rating"`
+	DewRisk                  string     `json:"dew_risk"`
+	DewStart                 *time.Time `json:"dew_start"`
+	DewHeaterPower           int        `json:"dew_heater_power_percent"`
+	FogStart                 *time.Time `json:"fog_start"`
+	MaxFogProbability        int        `json:"max_fog_probability_percent"`
+}
+
+// OutlookNight is the JSON representation of one night of the multi-night outlook
+type OutlookNight struct {
+	Date  string `json:"date"`
+	Sun   Sun    `json:"sun"`
+	Moon  Moon   `json:"moon"`
+	Night Night  `json:"night"`
+}
+
+// Hour is the JSON representation of a single forecast hour
+type Hour struct {
+	DateTime                 time.Time `json:"-"`
+	Time                     string    `json:"time"`
+	Clouds                   int       `json:"cloud_cover_percent"`
+	CloudsLow                int       `json:"cloud_cover_low_percent"`
+	CloudsMid                int       `json:"cloud_cover_mid_percent"`
+	CloudsHigh               int       `json:"cloud_cover_high_percent"`
+	EffectiveClouds          int       `json:"effective_cloud_cover_percent"`
+	PrecipitationProbability int       `json:"precipitation_probability_percent"`
+	Visibility               *float64  `json:"visibility_m,omitempty"`
+	FogProbability           int       `json:"fog_probability_percent"`
+	Temperature              float64   `json:"temperature_celsius"`
+	DewPoint                 float64   `json:"dew_point_celsius"`
+	Humidity                 int       `json:"humidity_percent"`
+	WindSpeed                float64   `json:"wind_speed_kmh"`
+	WindDirection            float64   `json:"wind_direction_degrees"`
+	WindGusts                float64   `json:"wind_gusts_kmh"`
+	ShakeRisk                string    `json:"shake_risk"`
+	Seeing                   int       `json:"seeing"`
+	SeeingArcsec             float64   `json:"seeing_arcsec,omitempty"`
+	JetStreamWind            float64   `json:"jet_stream_kmh,omitempty"`
+	Transparency             int       `json:"transparency"`
+	OpticalDepth             float64   `json:"optical_depth,omitempty"`
+	ClearProbability         *int      `json:"clear_probability_percent,omitempty"`
+	Rating                   int       `json:"rating"`
+	DewRisk                  string    `json:"dew_risk"`
+	DewHeaterPower           int       `json:"dew_heater_power_percent"`
+	MoonAltitude             float64   `json:"moon_altitude_degrees"`
+	MoonAzimuth              float64   `json:"moon_azimuth_degrees"`
+	MoonIllumination         float64   `json:"moon_illumination_percent"`
+	MoonEmoji                string    `json:"moon_emoji"`
+}
+
+// BuildForecast runs the forecast analysis for a place with a scoring profile and collects
+// the results for the night seen at the observer time at (see astro.ObserverTime), keeping
+// at most hours forecast hours from then on
+func BuildForecast(place domain.Place, weather domain.WeatherData, hours int, profile forecast.Profile, at time.Time) Forecast {
+	forecastData := forecast.GenerateForecastDataWithProfile(weather, profile)
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, at)
+	moonInfo := astro.GetMoonInfo(place.Latitude, place.Longitude, at)
+	nightForecast := forecast.AnalyzeNight(forecastData, place.Latitude, place.Longitude, sunInfo, profile.Thresholds)
+	forecasted := forecast.CoversNight(forecastData, sunInfo)
+
+	var clearIntervals []astro.Interval
+	targetWindows := nightForecast.DarkWindows
+	if forecasted {
+		clearIntervals = nightForecast.ClearIntervals()
+		targetWindows = clearIntervals
+	}
+	targets := deepsky.BestTargets(place.Latitude, place.Longitude, targetWindows, profile.Thresholds.MinTargetAltitude, deepsky.DefaultCount)
+
+	return Forecast{
+		Place:      NewPlace(place),
+		Timezone:   weather.Timezone,
+		Profile:    profile.Name,
+		Date:       at.Format(time.DateOnly),
+		Forecasted: forecasted,
+		Sun:        NewSun(sunInfo),
+		Moon:       NewMoon(moonInfo),
+		Night:      NewNight(nightForecast),
+		Outlook:    NewOutlook(forecast.AnalyzeNights(forecastData, place.Latitude, place.Longitude, profile.Thresholds)),
+		Planets:    NewPlanets(planets.GetVisibility(place.Latitude, place.Longitude, sunInfo.Night()), clearIntervals),
+		Targets:    NewTargets(targets),
+		Hours:      NewHours(UpcomingHours(forecastData, at, hours)),
+	}
+}
+
+// NewPlace converts a place to its JSON representation
+func NewPlace(place domain.Place) Place {
+	return Place{
+		Name:      place.Name,
+		Address:   place.Address,
+		Latitude:  place.Latitude,
+		Longitude: place.Longitude,
+		Profile:   place.Profile,
+	}
+}
+
+// NewSun converts sun information to its JSON representation
+func NewSun(sunInfo astro.SunInfo) Sun {
+	sun := Sun{
+		Sunset:          knownTime(sunInfo.Sunset),
+		Dusk:            knownTime(sunInfo.Dusk),
+		Dawn:            knownTime(sunInfo.Dawn),
+		Sunrise:         knownTime(sunInfo.Sunrise),
+		Darkness:        sunInfo.Darkness.String(),
+		DarkestTime:     sunInfo.DarkestTime,
+		DarkestAltitude: math.Round(sunInfo.DarkestAltitude*10) / 10,
+		PolarNight:      sunInfo.PolarNight,
+	}
+	if band := sunInfo.DarkestBand; !band.Start.IsZero() {
+		sun.DarkestBand = &Interval{Start: band.Start, End: band.End}
+	}
+	return sun
+}
+
+// NewMoon converts moon information to its JSON representation
+func NewMoon(moonInfo astro.MoonInfo) Moon {
+	return Moon{
+		Phase:        moonInfo.PhaseName,
+		Emoji:        moonInfo.PhaseEmoji,
+		Illumination: moonInfo.Illumination,
+		Moonrise:     knownTime(moonInfo.Moonrise),
+		Moonset:      knownTime(moonInfo.Moonset),
+	}
+}
+
+// NewNight converts a night forecast to its JSON representation
+func NewNight(night forecast.NightForecast) Night {
+	windows := make([]Window, len(night.Windows))
+	for i, window := range night.Windows {
+		windows[i] = NewWindow(window)
+	}
+	var bestPeriod *Window
+	if len(windows) > 0 {
+		bestPeriod = &windows[0]
+	}
+
+	var dewStart *time.Time
+	if !night.DewStart.IsZero() {
+		dewStart = &night.DewStart
+	}
+	var fogStart *time.Time
+	if !night.FogStart.IsZero() {
+		fogStart = &night.FogStart
+	}
+
+	return Night{
+		DarkWindows:              NewIntervals(night.DarkWindows),
+		BestPeriod:               bestPeriod,
+		Windows:                  windows,
+		CloudCover:               night.DisplayCloudCover,
+		MaxCloudCover:            night.ExtremeCloudCover,
+		Temperature:              night.NightlyTemperature,
+		Humidity:                 night.NightlyHumidity,
+		WindSpeed:                night.NightlyWindSpeed,
+		WindDirection:            night.NightlyWindDirection,
+		WindDirectionText:        night.WindDirectionText,
+		MaxWindGusts:             night.MaxWindGusts,
+		ShakeRisk:                night.ShakeRisk.String(),
+		DewPoint:                 night.NightlyDewPoint,
+		PrecipitationProbability: night.MaxPrecipProbability,
+		Seeing:                   night.SeeingIndex,
+		Transparency:             night.TransparencyIndex,
+		Rating:                   night.Rating,
+		DewRisk:                  night.DewRisk.String(),
+		DewStart:                 dewStart,
+		DewHeaterPower:           night.DewHeaterPower,
+		FogStart:                 fogStart,
+		MaxFogProbability:        night.MaxFogProbability,
+	}
+}
+
+// NewWindow converts an observation window to its JSON representation
+func NewWindow(window forecast.ObservationWindow) Window {
+	return Window{
+		Start:      window.Start,
+		End:        window.End,
+		Hours:      window.Hours(),
+		CloudCover: int(math.Round(window.CloudCover)),
+		Seeing:     math.Round(window.Seeing*10) / 10,
+		Rating:     math.Round(window.Rating*10) / 10,
+		Confidence: knownPercent(window.Confidence),
+	}
+}
+
+// knownPercent returns nil for a negative, unknown percentage
+func knownPercent(percent int) *int {
+	if percent < 0 {
+		return nil
+	}
+	return &percent
+}
+
+// knownVisibility returns nil for an unknown (negative) visibility
+func knownVisibility(metres float64) *float64 {
+	if metres < 0 {
+		return nil
+	}
+	return &metres
+}
+
+// NewOutlook converts a multi-night outlook to its JSON representation
+func NewOutlook(nights []forecast.NightOutlook) []OutlookNight {
+	reports := make([]OutlookNight, len(nights))
+	for i, night := range nights {
+		reports[i] = OutlookNight{
+			Date:  night.Date.Format(time.DateOnly),
+			Sun:   NewSun(night.Sun),
+			Moon:  NewMoon(night.Moon),
+			Night: NewNight(night.Night),
+		}
+	}
+	return reports
+}
+
+// NewPlanets converts the visibility of the planets to their JSON representation. Their clear
+// periods are those within clearIntervals, unknown when it is nil.
+func NewPlanets(visibilities []planets.Visibility, clearIntervals []astro.Interval) []Planet {
+	reports := make([]Planet, len(visibilities))
+	for i, visibility := range visibilities {
+		reports[i] = Planet{
+			Name:            visibility.Planet.String(),
+			Rise:            knownTime(visibility.Rise),
+			Transit:         knownTime(visibility.Transit),
+			TransitAltitude: math.Round(visibility.TransitAltitude),
+			Set:             knownTime(visibility.Set),
+			MaxAltitude:     math.Round(visibility.MaxAltitude),
+			Magnitude:       math.Round(visibility.Magnitude*10) / 10,
+			Diameter:        math.Round(visibility.Diameter*10) / 10,
+			Elongation:      math.Round(visibility.Elongation),
+			Observable:      NewIntervals(visibility.Observable),
+		}
+		if clearIntervals != nil {
+			reports[i].Clear = NewIntervals(visibility.Clear(clearIntervals))
+		}
+	}
+	return reports
+}
+
+// NewTargets converts the suggested deep-sky targets to their JSON representation
+func NewTargets(targets []deepsky.Target) []Target {
+	reports := make([]Target, len(targets))
+	for i, target := range targets {
+		reports[i] = Target{
+			ID:              target.ID,
+			Designation:     target.Designation,
+			Name:            target.Name,
+			Type:            string(target.Type),
+			Constellation:   target.Constellation,
+			RightAscension:  math.Round(target.Position.RightAscension*100) / 100,
+			Declination:     math.Round(target.Position.Declination*100) / 100,
+			Magnitude:       knownMagnitude(target.Magnitude),
+			Size:            target.Size,
+			Up:              NewIntervals(target.Up),
+			Hours:           math.Round(target.Hours()*10) / 10,
+			Transit:         target.Transit,
+			TransitAltitude: math.Round(target.TransitAltitude),
+			MoonSeparation:  math.Round(target.MoonSeparation),
+			MoonUp:          target.MoonUp,
+			Score:           math.Round(target.Score*10) / 10,
+		}
+	}
+	return reports
+}
+
+// knownMagnitude returns nil for an unknown (NaN) magnitude
+func knownMagnitude(magnitude float64) *float64 {
+	if math.IsNaN(magnitude) {
+		return nil
+	}
+	return &magnitude
+}
+
+// NewIntervals converts spans of time to their JSON representation
+func NewIntervals(intervals []astro.Interval) []Interval {
+	reports := make([]Interval, len(intervals))
+	for i, interval := range intervals {
+		reports[i] = Interval{Start: interval.Start, End: interval.End}
+	}
+	return reports
+}
+
+// knownTime returns t, or nil when it is zero
+func knownTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// NewHours converts forecast hours to their JSON representation
+func NewHours(hours []forecast.ForecastHour) []Hour {
+	reports := make([]Hour, len(hours))
+	for i, hour := range hours {
+		reports[i] = Hour{
+			DateTime:                 hour.DateTime,
+			Time:                     hour.DateTime.Format(time.RFC3339),
+			Clouds:                   hour.Clouds,
+			CloudsLow:                hour.CloudsLow,
+			CloudsMid:                hour.CloudsMid,
+			CloudsHigh:               hour.CloudsHigh,
+			EffectiveClouds:          hour.EffectiveClouds,
+			PrecipitationProbability: hour.PrecipitationProbability,
+			Visibility:               knownVisibility(hour.Visibility),
+			FogProbability:           hour.FogProbability,
+			Temperature:              hour.Temperature,
+			DewPoint:                 hour.DewPoint,
+			Humidity:                 hour.Humidity,
+			WindSpeed:                hour.WindSpeed,
+			WindDirection:            hour.WindDirection,
+			WindGusts:                hour.WindGusts,
+			ShakeRisk:                hour.ShakeRisk.String(),
+			Seeing:                   hour.Seeing,
+			SeeingArcsec:             math.Round(hour.SeeingArcsec*100) / 100,
+			JetStreamWind:            math.Round(hour.JetStreamWind*10) / 10,
+			Transparency:             hour.Transparency,
+			OpticalDepth:             math.Round(hour.OpticalDepth*1000) / 1000,
+			ClearProbability:         knownPercent(hour.ClearProbability),
+			Rating:                   hour.Rating,
+			DewRisk:                  hour.DewRisk.String(),
+			DewHeaterPower:           hour.DewHeaterPower,
+			MoonAltitude:             math.Round(hour.Moon.Altitude*10) / 10,
+			MoonAzimuth:              math.Round(hour.Moon.Azimuth*10) / 10,
+			MoonIllumination:         math.Round(hour.Moon.Illumination*10) / 10,
+			MoonEmoji:                hour.Moon.PhaseEmoji,
+		}
+	}
+	return reports
+}
+
+// UpcomingHours returns at most count forecast hours starting with the first one after now
+func UpcomingHours(forecastData []forecast.ForecastHour, now time.Time, count int) []forecast.ForecastHour {
+	startIndex := len(forecastData)
+	for i, hour := range forecastData {
+		if hour.DateTime.After(now) {
+			startIndex = i
+			break
+		}
+	}
+
+	end := min(startIndex+count, len(forecastData))
+	return forecastData[startIndex:end]
+}
+
+// ModelComparison is the JSON representation of the cloud cover of several weather models side by side
+type ModelComparison struct {
+	Place          Place       `json:"place"`
+	Timezone       string      `json:"timezone"`
+	Models         []string    `json:"models"`
+	NightAgreement *int        `json:"night_agreement_percent,omitempty"`
+	Hours          []ModelHour `json:"hours"`
+}
+
+// ModelHour is the JSON representation of the model comparison of a single hour
+type ModelHour struct {
+	DateTime   time.Time `json:"-"`
+	Time       string    `json:"time"`
+	CloudCover []*int    `json:"cloud_cover_percent"` // in the order of the models, null when unknown
+	Mean       int       `json:"mean_cloud_cover_percent"`
+	Agreement  *int      `json:"agreement_percent,omitempty"`
+}
+
+// BuildModelComparison scores the agreement of the models and keeps at most hours upcoming hours
+func BuildModelComparison(place domain.Place, comparison domain.ModelComparison, hours int) ModelComparison {
+	modelHours := forecast.CompareModels(comparison)
+	now := time.Now()
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, now.In(comparison.Location()))
+
+	models := make([]string, len(comparison.Models))
+	for i, series := range comparison.Models {
+		models[i] = series.Model
+	}
+
+	var reports []ModelHour
+	for _, hour := range modelHours {
+		if len(reports) >= hours {
+			break
+		}
+		if !hour.DateTime.After(now) {
+			continue
+		}
+		reports = append(reports, NewModelHour(hour))
+	}
+
+	return ModelComparison{
+		Place:          NewPlace(place),
+		Timezone:       comparison.Timezone,
+		Models:         models,
+		NightAgreement: knownPercent(forecast.NightModelAgreement(modelHours, sunInfo)),
+		Hours:          reports,
+	}
+}
+
+// NewModelHour converts the model comparison of an hour to its JSON representation
+func NewModelHour(hour forecast.ModelHour) ModelHour {
+	clouds := make([]*int, len(hour.CloudCover))
+	for i, value := range hour.CloudCover {
+		clouds[i] = knownPercent(value)
+	}
+
+	return ModelHour{
+		DateTime:   hour.DateTime,
+		Time:       hour.DateTime.Format(time.RFC3339),
+		CloudCover: clouds,
+		Mean:       int(math.Round(hour.Mean)),
+		Agreement:  knownPercent(hour.Agreement),
+	}
+}
+
+// Verification is the JSON representation of the forecast track record of a place
+type Verification struct {
+	Place   Place                `json:"place"`
+	Results []VerificationResult `json:"results"`
+}
+
+// VerificationResult is the JSON representation of the forecast errors of one model at one lead time
+type VerificationResult struct {
+	Model            string  `json:"model"`
+	LeadDay          int     `json:"lead_day"`
+	Hours            int     `json:"hours"`
+	CloudBias        float64 `json:"cloud_cover_bias_percent"`
+	CloudMAE         float64 `json:"cloud_cover_mae_percent"`
+	ClearHitRate     float64 `json:"clear_hit_rate_percent"`
+	TemperatureHours int     `json:"temperature_hours"`
+	TemperatureBias  float64 `json:"temperature_bias_celsius"`
+	TemperatureMAE   float64 `json:"temperature_mae_celsius"`
+}
+
+// NewVerification converts the verification results of a place to their JSON representation
+func NewVerification(place domain.Place, results []verification.Result) Verification {
+	reports := make([]VerificationResult, len(results))
+	for i, result := range results {
+		reports[i] = VerificationResult{
+			Model:            result.Model,
+			LeadDay:          result.LeadDay,
+			Hours:            result.Hours,
+			CloudBias:        math.Round(result.CloudBias*10) / 10,
+			CloudMAE:         math.Round(result.CloudMAE*10) / 10,
+			ClearHitRate:     math.Round(result.ClearHitRate*10) / 10,
+			TemperatureHours: result.TemperatureHours,
+			TemperatureBias:  math.Round(result.TemperatureBias*10) / 10,
+			TemperatureMAE:   math.Round(result.TemperatureMAE*10) / 10,
+		}
+	}
+	return Verification{Place: NewPlace(place), Results: reports}
+}
@@ -0,0 +1,385 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/domain/astro"
+	"driffaud.fr/odin/internal/forecast"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+	"driffaud.fr/odin/internal/util"
+)
+
+// APIVersion is the version reported in every response envelope
+const APIVersion = "v1"
+
+const shutdownTimeout = 10 * time.Second
+
+// Server exposes the forecast analysis as a versioned JSON HTTP API
+type Server struct {
+	cfg         config.Config
+	weather     *openmeteo.Client
+	favorites   *storage.FavoritesStore
+	favoritesMu sync.Mutex
+	mux         *http.ServeMux
+}
+
+// Response is the envelope of every API response
+type Response struct {
+	APIVersion string `json:"api_version"`
+	Data       any    `json:"data,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NightResponse is the payload of the /v1/night endpoint
+type NightResponse struct {
+	Place      report.Place `json:"place"`
+	Timezone   string       `json:"timezone"`
+	Profile    string       `json:"profile"`
+	Date       string       `json:"date"`
+	Forecasted bool         `json:"forecasted"`
+	Sun        report.Sun   `json:"sun"`
+	Night      report.Night `json:"night"`
+}
+
+// AstroResponse is the payload of the /v1/astro endpoint
+type AstroResponse struct {
+	Place report.Place `json:"place"`
+	Date  string       `json:"date"`
+	Sun   report.Sun   `json:"sun"`
+	Moon  report.Moon  `json:"moon"`
+}
+
+// New creates a server fetching forecasts with the given client and reading the given favorites
+func New(cfg config.Config, weather *openmeteo.Client, favorites *storage.FavoritesStore) *Server {
+	s := &Server{
+		cfg:       cfg,
+		weather:   weather,
+		favorites: favorites,
+		mux:       http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /v1/forecast", s.handleForecast)
+	s.mux.HandleFunc("GET /v1/night", s.handleNight)
+	s.mux.HandleFunc("GET /v1/astro", s.handleAstro)
+	s.mux.HandleFunc("GET /v1/models", s.handleModels)
+	s.mux.HandleFunc("GET /v1/favorites", s.handleFavorites)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no endpoint %s", r.URL.Path))
+	})
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on addr until ctx is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the API on listener until ctx is cancelled, then shuts down gracefully
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleForecast(w http.ResponseWriter, r *http.Request) {
+	place, err := s.placeFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hours := s.cfg.Forecast.TableHours
+	if value := r.URL.Query().Get("hours"); value != "" {
+		hours, err = strconv.Atoi(value)
+		if err != nil || hours < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid hours %q", value))
+			return
+		}
+	}
+
+	profile, err := s.cfg.ResolveProfile(r.URL.Query().Get("profile"), place)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	date, err := dateFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	weather, err := s.weather.GetWeather(place.Latitude, place.Longitude)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	at, err := observerTime(date, weather.Location())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeData(w, report.BuildForecast(place, weather, hours, profile, at))
+}
+
+func (s *Server) handleNight(w http.ResponseWriter, r *http.Request) {
+	place, err := s.placeFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	profile, err := s.cfg.ResolveProfile(r.URL.Query().Get("profile"), place)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	date, err := dateFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	weather, err := s.weather.GetWeather(place.Latitude, place.Longitude)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	forecastData := forecast.GenerateForecastDataWithProfile(weather, profile)
+	at, err := observerTime(date, weather.Location())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	sunInfo := astro.GetSunInfo(place.Latitude, place.Longitude, at)
+	nightForecast := forecast.AnalyzeNight(forecastData, place.Latitude, place.Longitude, sunInfo, profile.Thresholds)
+
+	writeData(w, NightResponse{
+		Place:      report.NewPlace(place),
+		Timezone:   weather.Timezone,
+		Profile:    profile.Name,
+		Date:       at.Format(time.DateOnly),
+		Forecasted: forecast.CoversNight(forecastData, sunInfo),
+		Sun:        report.NewSun(sunInfo),
+		Night:      report.NewNight(nightForecast),
+	})
+}
+
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	place, err := s.placeFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hours := s.cfg.Forecast.TableHours
+	if value := r.URL.Query().Get("hours"); value != "" {
+		hours, err = strconv.Atoi(value)
+		if err != nil || hours < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid hours %q", value))
+			return
+		}
+	}
+
+	comparison, err := s.weather.GetModelComparison(place.Latitude, place.Longitude)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	writeData(w, report.BuildModelComparison(place, comparison, hours))
+}
+
+func (s *Server) handleAstro(w http.ResponseWriter, r *http.Request) {
+	place, err := s.placeFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	date, err := dateFromQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The site's time zone can be given as an IANA name to spare the lookup
+	var loc *time.Location
+	if name := r.URL.Query().Get("tz"); name != "" {
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tz %q", name))
+			return
+		}
+	} else {
+		loc, err = s.weather.GetLocation(place.Latitude, place.Longitude)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+	}
+
+	at, err := observerTime(date, loc)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeData(w, AstroResponse{
+		Place: report.NewPlace(place),
+		Date:  at.Format(time.DateOnly),
+		Sun:   report.NewSun(astro.GetSunInfo(place.Latitude, place.Longitude, at)),
+		Moon:  report.NewMoon(astro.GetMoonInfo(place.Latitude, place.Longitude, at)),
+	})
+}
+
+func (s *Server) handleFavorites(w http.ResponseWriter, r *http.Request) {
+	favorites, err := s.loadFavorites()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	places := make([]report.Place, len(favorites))
+	for i, fav := range favorites {
+		places[i] = report.NewPlace(fav)
+	}
+	writeData(w, places)
+}
+
+// loadFavorites reloads the favorites file so changes made from the CLI are visible
+func (s *Server) loadFavorites() ([]domain.Place, error) {
+	s.favoritesMu.Lock()
+	defer s.favoritesMu.Unlock()
+
+	if s.favorites.FilePath != "" {
+		if err := s.favorites.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load favorites: %w", err)
+		}
+	}
+
+	return append([]domain.Place(nil), s.favorites.Favorites...), nil
+}
+
+// placeFromQuery resolves the place from the lat/lon or favorite query parameters
+func (s *Server) placeFromQuery(r *http.Request) (domain.Place, error) {
+	query := r.URL.Query()
+
+	if name := query.Get("favorite"); name != "" {
+		favorites, err := s.loadFavorites()
+		if err != nil {
+			return domain.Place{}, err
+		}
+		store := storage.FavoritesStore{Favorites: favorites}
+		if place, ok := store.FindFavorite(name); ok {
+			return place, nil
+		}
+		return domain.Place{}, fmt.Errorf("no favorite named '%s'", name)
+	}
+
+	latValue, lonValue := query.Get("lat"), query.Get("lon")
+	if latValue == "" || lonValue == "" {
+		return domain.Place{}, errors.New("lat and lon, or favorite, query parameters are required")
+	}
+
+	lat, err := strconv.ParseFloat(latValue, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return domain.Place{}, fmt.Errorf("invalid latitude %q", latValue)
+	}
+	lon, err := strconv.ParseFloat(lonValue, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return domain.Place{}, fmt.Errorf("invalid longitude %q", lonValue)
+	}
+
+	return domain.Place{
+		Name:      fmt.Sprintf("%.4f, %.4f", lat, lon),
+		Latitude:  lat,
+		Longitude: lon,
+	}, nil
+}
+
+// dateFromQuery reads the night to analyze from the date query parameter, empty for tonight.
+// Only its syntax is checked here, as by the --date flag of the CLI: see observerTime.
+func dateFromQuery(r *http.Request) (string, error) {
+	value := r.URL.Query().Get("date")
+	if value == "" {
+		return "", nil
+	}
+	if _, err := util.ParseDate(value, time.Now()); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// observerTime returns the time at which to look at the night of date at a site in the time
+// zone loc, refusing a night already past there
+func observerTime(date string, loc *time.Location) (time.Time, error) {
+	now := time.Now()
+	nightDate, err := util.ParseNightDate(date, loc, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return astro.ObserverTime(nightDate, loc, now), nil
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, Response{APIVersion: APIVersion, Data: data})
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, Response{APIVersion: APIVersion, Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/config"
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/api/openmeteo"
+	"driffaud.fr/odin/internal/platform/storage"
+	"driffaud.fr/odin/internal/report"
+	"driffaud.fr/odin/internal/util"
+)
+
+// stubWeather returns three days of clear hourly weather starting at midnight today
+func stubWeather() domain.WeatherData {
+	var weather domain.WeatherData
+	weather.Timezone = "UTC"
+
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := range 72 {
+		t := start.Add(time.Duration(i) * time.Hour)
+		weather.Hourly.Time = append(weather.Hourly.Time, t.Format(util.ISO8601Format))
+		weather.Hourly.CloudCover = append(weather.Hourly.CloudCover, 5)
+		weather.Hourly.Temperature = append(weather.Hourly.Temperature, 12)
+		weather.Hourly.DewPoint = append(weather.Hourly.DewPoint, 4)
+		weather.Hourly.RelativeHumidity = append(weather.Hourly.RelativeHumidity, 60)
+		weather.Hourly.WindSpeed = append(weather.Hourly.WindSpeed, 5)
+		weather.Hourly.WindDirection = append(weather.Hourly.WindDirection, 270)
+		weather.Hourly.PrecipitationProbability = append(weather.Hourly.PrecipitationProbability, 0)
+	}
+
+	return weather
+}
+
+func newTestServer(t *testing.T, upstreamStatus int) (*httptest.Server, *int) {
+	t.Helper()
+
+	upstreamCalls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		if r.URL.Query().Get("latitude") == "" || r.URL.Query().Get("longitude") == "" {
+			t.Errorf("upstream request without coordinates: %s", r.URL)
+		}
+		if upstreamStatus != http.StatusOK {
+			w.WriteHeader(upstreamStatus)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(stubWeather())
+	}))
+	t.Cleanup(upstream.Close)
+
+	favorites := &storage.FavoritesStore{
+		FilePath: filepath.Join(t.TempDir(), "favorites.json"),
+		Favorites: []domain.Place{
+			{Name: "Pic du Midi", Latitude: 42.936, Longitude: 0.142},
+		},
+	}
+	if err := favorites.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &openmeteo.Client{BaseURL: upstream.URL, HTTPClient: upstream.Client()}
+	api := httptest.NewServer(New(config.Default(), client, favorites))
+	t.Cleanup(api.Close)
+
+	return api, &upstreamCalls
+}
+
+func getJSON(t *testing.T, url string, wantStatus int, data any) Response {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s: status = %d, want %d", url, resp.StatusCode, wantStatus)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s: Content-Type = %q, want application/json", url, ct)
+	}
+
+	envelope := Response{Data: data}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("GET %s: decoding response: %v", url, err)
+	}
+	if envelope.APIVersion != APIVersion {
+		t.Errorf("GET %s: api_version = %q, want %q", url, envelope.APIVersion, APIVersion)
+	}
+
+	return envelope
+}
+
+func TestForecast(t *testing.T) {
+	api, calls := newTestServer(t, http.StatusOK)
+
+	var forecast report.Forecast
+	getJSON(t, api.URL+"/v1/forecast?lat=42.9&lon=0.14&hours=6", http.StatusOK, &forecast)
+
+	if *calls != 1 {
+		t.Errorf("upstream calls = %d, want 1", *calls)
+	}
+	if len(forecast.Hours) != 6 {
+		t.Fatalf("len(hours) = %d, want 6", len(forecast.Hours))
+	}
+	if _, err := time.Parse(time.RFC3339, forecast.Hours[0].Time); err != nil {
+		t.Errorf("hours[0].time = %q, want RFC 3339: %v", forecast.Hours[0].Time, err)
+	}
+	if forecast.Hours[0].Clouds != 5 {
+		t.Errorf("hours[0].cloud_cover_percent = %d, want 5", forecast.Hours[0].Clouds)
+	}
+	if forecast.Place.Latitude != 42.9 || forecast.Place.Longitude != 0.14 {
+		t.Errorf("place = %+v, want coordinates 42.9, 0.14", forecast.Place)
+	}
+}
+
+func TestNightForFavorite(t *testing.T) {
+	api, _ := newTestServer(t, http.StatusOK)
+
+	var night NightResponse
+	getJSON(t, api.URL+"/v1/night?favorite=pic+du+midi&profile=planetary", http.StatusOK, &night)
+
+	if night.Profile != "planetary" {
+		t.Errorf("profile = %q, want planetary", night.Profile)
+	}
+	if night.Place.Name != "Pic du Midi" {
+		t.Errorf("place.name = %q, want Pic du Midi", night.Place.Name)
+	}
+	if night.Night.BestPeriod == nil {
+		t.Fatal("best_period is null for a clear night")
+	}
+	if night.Night.BestPeriod.CloudCover != 5 {
+		t.Errorf("best_period.cloud_cover_percent = %d, want 5", night.Night.BestPeriod.CloudCover)
+	}
+}
+
+func TestAstroWithTimezoneDoesNotCallUpstream(t *testing.T) {
+	api, calls := newTestServer(t, http.StatusOK)
+
+	var astro AstroResponse
+	getJSON(t, api.URL+"/v1/astro?lat=42.9&lon=0.14&tz=Asia/Tokyo", http.StatusOK, &astro)
+
+	if *calls != 0 {
+		t.Errorf("upstream calls = %d, want 0", *calls)
+	}
+	if astro.Sun.Sunset == nil {
+		t.Fatal("sun.sunset is null")
+	}
+	if _, offset := astro.Sun.Sunset.Zone(); offset != 9*3600 {
+		t.Errorf("sun.sunset offset = %d s, want %d s of Asia/Tokyo", offset, 9*3600)
+	}
+}
+
+func TestAstroUsesSiteTimezone(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("timezone") != "auto" {
+			t.Errorf("time zone lookup without timezone=auto: %s", r.URL)
+		}
+		_ = json.NewEncoder(w).Encode(domain.WeatherData{Timezone: "America/New_York"})
+	}))
+	t.Cleanup(upstream.Close)
+
+	client := &openmeteo.Client{BaseURL: upstream.URL, HTTPClient: upstream.Client()}
+	api := httptest.NewServer(New(config.Default(), client, &storage.FavoritesStore{}))
+	t.Cleanup(api.Close)
+
+	var astro AstroResponse
+	getJSON(t, api.URL+"/v1/astro?lat=40.7&lon=-74", http.StatusOK, &astro)
+	if astro.Sun.Sunset == nil {
+		t.Fatal("sun.sunset is null")
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, want := astro.Sun.Sunset.In(newYork).Zone()
+	if _, offset := astro.Sun.Sunset.Zone(); offset != want {
+		t.Errorf("sun.sunset offset = %d s, want %d s of the site", offset, want)
+	}
+	if hour := astro.Sun.Sunset.Hour(); hour < 16 || hour > 21 {
+		t.Errorf("sun.sunset = %v, want a local evening time", astro.Sun.Sunset)
+	}
+}
+
+func TestAstroMidnightSunTimesAreNull(t *testing.T) {
+	api, _ := newTestServer(t, http.StatusOK)
+
+	// The next summer solstice in Svalbard, where the sun does not set
+	now := time.Now()
+	solstice := time.Date(now.Year(), time.June, 21, 0, 0, 0, 0, time.UTC)
+	if solstice.Before(now) {
+		solstice = solstice.AddDate(1, 0, 0)
+	}
+
+	var astro struct {
+		Sun map[string]any `json:"sun"`
+	}
+	getJSON(t, api.URL+"/v1/astro?lat=78.2&lon=15.6&tz=Arctic/Longyearbyen&date="+solstice.Format(time.DateOnly), http.StatusOK, &astro)
+
+	for _, name := range []string{"sunset", "dusk", "dawn", "sunrise"} {
+		value, ok := astro.Sun[name]
+		if !ok || value != nil {
+			t.Errorf("sun.%s = %v, want null", name, value)
+		}
+	}
+}
+
+func TestFavorites(t *testing.T) {
+	api, _ := newTestServer(t, http.StatusOK)
+
+	var favorites []report.Place
+	getJSON(t, api.URL+"/v1/favorites", http.StatusOK, &favorites)
+
+	if len(favorites) != 1 || favorites[0].Name != "Pic du Midi" {
+		t.Errorf("favorites = %+v, want [Pic du Midi]", favorites)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		upstreamStatus int
+		wantStatus     int
+	}{
+		{"missing coordinates", "/v1/forecast", http.StatusOK, http.StatusBadRequest},
+		{"invalid latitude", "/v1/night?lat=91&lon=0", http.StatusOK, http.StatusBadRequest},
+		{"invalid hours", "/v1/forecast?lat=1&lon=1&hours=x", http.StatusOK, http.StatusBadRequest},
+		{"unknown favorite", "/v1/night?favorite=nowhere", http.StatusOK, http.StatusBadRequest},
+		{"invalid date", "/v1/night?lat=1&lon=1&date=someday", http.StatusOK, http.StatusBadRequest},
+		{"past date", "/v1/night?lat=1&lon=1&date=2000-01-01", http.StatusOK, http.StatusBadRequest},
+		{"past astro date", "/v1/astro?lat=1&lon=1&date=2000-01-01", http.StatusOK, http.StatusBadRequest},
+		{"invalid astro tz", "/v1/astro?lat=1&lon=1&tz=Nowhere/City", http.StatusOK, http.StatusBadRequest},
+		{"astro time zone lookup failure", "/v1/astro?lat=1&lon=1", http.StatusInternalServerError, http.StatusBadGateway},
+		{"upstream failure", "/v1/forecast?lat=1&lon=1", http.StatusInternalServerError, http.StatusBadGateway},
+		{"unknown endpoint", "/v2/forecast", http.StatusOK, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, _ := newTestServer(t, tt.upstreamStatus)
+
+			envelope := getJSON(t, api.URL+tt.path, tt.wantStatus, nil)
+			if envelope.Error == "" {
+				t.Error("error message is empty")
+			}
+		})
+	}
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- New(config.Default(), openmeteo.DefaultClient, &storage.FavoritesStore{}).Serve(ctx, listener)
+	}()
+
+	getJSON(t, "http://"+listener.Addr().String()+"/v1/favorites", http.StatusOK, nil)
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve returned %v after shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
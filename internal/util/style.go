@@ -33,4 +33,15 @@ var (
 			MarginTop(1).
 			Foreground(lipgloss.Color("105")).
 			Bold(true)
+
+	OutlookStyle = lipgloss.NewStyle().
+			MarginTop(1).
+			MarginLeft(4)
+
+	TargetsStyle = lipgloss.NewStyle().
+			MarginTop(1)
+
+	ErrorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true)
 )
@@ -1,6 +1,81 @@
 package util
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 const (
 	// ISO8601 format
 	ISO8601Format = "2006-01-02T15:04"
 )
+
+// ParseHourlyTimes parses a series of consecutive hours given as wall clock times in loc.
+// The hour repeated when clocks go back is told apart by following the previous hour.
+// Times that cannot be parsed are left zero.
+func ParseHourlyTimes(times []string, loc *time.Location) []time.Time {
+	parsed := make([]time.Time, len(times))
+
+	for i, timeStr := range times {
+		if i > 0 && !parsed[i-1].IsZero() {
+			if next := parsed[i-1].Add(time.Hour); next.Format(ISO8601Format) == timeStr {
+				parsed[i] = next
+				continue
+			}
+		}
+		parsed[i], _ = time.ParseInLocation(ISO8601Format, timeStr, loc)
+	}
+
+	return parsed
+}
+
+// ErrPastDate is returned by ParseNightDate for a night already past at the site
+var ErrPastDate = errors.New("the date must not be in the past")
+
+// ParseDate reads a calendar day given as YYYY-MM-DD, "today", "tomorrow" or "+N" days after
+// today, the day of now in its location. Only the date of the result is meaningful, it is midnight UTC.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case value == "today":
+		return today, nil
+	case value == "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case strings.HasPrefix(value, "+"):
+		days, err := strconv.Atoi(value[1:])
+		if err != nil || days < 0 {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, today, tomorrow or +N)", value)
+	}
+	return date, nil
+}
+
+// ParseNightDate reads the night to analyze at a site in the time zone loc, as ParseDate with
+// today taken at the site, and refuses a day already past there. The empty value is tonight,
+// the zero time.
+func ParseNightDate(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	now = now.In(loc)
+	date, err := ParseDate(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if today, _ := ParseDate("today", now); date.Before(today) {
+		return time.Time{}, ErrPastDate
+	}
+	return date, nil
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseNightDateAtTheSite(t *testing.T) {
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati") // UTC+14
+	if err != nil {
+		t.Fatal(err)
+	}
+	pagoPago, err := time.LoadLocation("Pacific/Pago_Pago") // UTC-11
+	if err != nil {
+		t.Fatal(err)
+	}
+	// November 6 at 02:00 in Kiritimati, still November 5 at 01:00 in Pago Pago
+	now := time.Date(2024, time.November, 5, 12, 0, 0, 0, time.UTC)
+
+	if _, err := ParseNightDate("2024-11-05", kiritimati, now); !errors.Is(err, ErrPastDate) {
+		t.Errorf("November 5 in Kiritimati: error %v, want %v", err, ErrPastDate)
+	}
+	if date, err := ParseNightDate("2024-11-05", pagoPago, now); err != nil || date.Day() != 5 {
+		t.Errorf("November 5 in Pago Pago = %v, %v, want November 5", date, err)
+	}
+	if date, err := ParseNightDate("today", kiritimati, now); err != nil || date.Day() != 6 {
+		t.Errorf("today in Kiritimati = %v, %v, want November 6", date, err)
+	}
+	if date, err := ParseNightDate("", kiritimati, now); err != nil || !date.IsZero() {
+		t.Errorf("empty date = %v, %v, want the zero time for tonight", date, err)
+	}
+}
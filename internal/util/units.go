@@ -0,0 +1,36 @@
+package util
+
+import "fmt"
+
+// Units selects how temperatures and speeds are displayed.
+// Values are always computed in metric units and only converted for display.
+type Units string
+
+const (
+	Metric   Units = "metric"
+	Imperial Units = "imperial"
+)
+
+// Temperature formats a temperature given in degrees Celsius
+func (u Units) Temperature(celsius float64, decimals int) string {
+	if u == Imperial {
+		return fmt.Sprintf("%.*f°F", decimals, celsius*9/5+32)
+	}
+	return fmt.Sprintf("%.*f°C", decimals, celsius)
+}
+
+// WindSpeed formats a speed given in kilometres per hour
+func (u Units) WindSpeed(kmh float64, decimals int) string {
+	if u == Imperial {
+		return fmt.Sprintf("%.*f mph", decimals, kmh/1.609344)
+	}
+	return fmt.Sprintf("%.*f km/h", decimals, kmh)
+}
+
+// TemperatureDifference formats a temperature difference given in degrees Celsius
+func (u Units) TemperatureDifference(celsius float64, decimals int) string {
+	if u == Imperial {
+		return fmt.Sprintf("%+.*f°F", decimals, celsius*9/5)
+	}
+	return fmt.Sprintf("%+.*f°C", decimals, celsius)
+}
@@ -0,0 +1,113 @@
+package verification
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/storage"
+)
+
+// coordinateTolerance is the distance in degrees, about 10 m, within which a fetched forecast
+// belongs to a favorite. The coordinates sent to the API can be rounded on the way.
+const coordinateTolerance = 1e-4
+
+// Recorder keeps the forecasts fetched for favorite places in the history store.
+// Recording is best effort: a forecast that cannot be stored is simply not verified.
+type Recorder struct {
+	mu        sync.Mutex
+	favorites []domain.Place
+	history   *storage.HistoryStore
+}
+
+// NewRecorder creates a recorder for the given favorites, keeping their forecasts in the
+// history store of the application
+func NewRecorder(favorites []domain.Place) (*Recorder, error) {
+	history, err := storage.NewHistoryStore()
+	if err != nil {
+		return nil, err
+	}
+	return NewRecorderWithHistory(favorites, history), nil
+}
+
+// NewRecorderWithHistory creates a recorder for the given favorites keeping their forecasts
+// in history
+func NewRecorderWithHistory(favorites []domain.Place, history *storage.HistoryStore) *Recorder {
+	r := &Recorder{history: history}
+	r.SetFavorites(favorites)
+	return r
+}
+
+// SetFavorites replaces the places whose forecasts are recorded
+func (r *Recorder) SetFavorites(favorites []domain.Place) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.favorites = append([]domain.Place(nil), favorites...)
+}
+
+// RecordWeather stores the cloud cover and temperature forecast of a favorite
+func (r *Recorder) RecordWeather(lat, lon float64, model string, weather domain.WeatherData) {
+	r.record(lat, lon, domain.ForecastRecord{
+		Model:       model,
+		FetchedAt:   time.Now(),
+		Timezone:    weather.Timezone,
+		UTCOffset:   weather.UTCOffset,
+		Time:        weather.Hourly.Time,
+		CloudCover:  weather.Hourly.CloudCover,
+		Temperature: weather.Hourly.Temperature,
+	})
+}
+
+// RecordModels stores the cloud cover forecast of every compared model of a favorite,
+// writing the history once
+func (r *Recorder) RecordModels(lat, lon float64, comparison domain.ModelComparison) {
+	records := make([]domain.ForecastRecord, len(comparison.Models))
+	for i, series := range comparison.Models {
+		records[i] = domain.ForecastRecord{
+			Model:      series.Model,
+			FetchedAt:  time.Now(),
+			Timezone:   comparison.Timezone,
+			UTCOffset:  comparison.UTCOffset,
+			Time:       comparison.Time,
+			CloudCover: series.CloudCover,
+		}
+	}
+	r.record(lat, lon, records...)
+}
+
+func (r *Recorder) record(lat, lon float64, records ...domain.ForecastRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	place, ok := findFavoriteAt(r.favorites, lat, lon)
+	if !ok {
+		return
+	}
+
+	var kept []domain.ForecastRecord
+	for _, record := range records {
+		if len(record.Time) == 0 {
+			continue
+		}
+		record.Place = place.Name
+		record.Latitude = place.Latitude
+		record.Longitude = place.Longitude
+		kept = append(kept, record)
+	}
+	_ = r.history.AddRecords(kept...)
+}
+
+// findFavoriteAt returns the favorite nearest to the given coordinates, within coordinateTolerance
+func findFavoriteAt(favorites []domain.Place, lat, lon float64) (domain.Place, bool) {
+	var nearest domain.Place
+	found := false
+	best := math.Inf(1)
+	for _, fav := range favorites {
+		distance := math.Max(math.Abs(fav.Latitude-lat), math.Abs(fav.Longitude-lon))
+		if distance <= coordinateTolerance && distance < best {
+			nearest, found, best = fav, true, distance
+		}
+	}
+	return nearest, found
+}
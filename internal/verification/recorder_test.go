@@ -0,0 +1,116 @@
+package verification
+
+import (
+	"path/filepath"
+	"testing"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/storage"
+)
+
+var testFavorites = []domain.Place{
+	{Name: "Home", Latitude: 45.1885, Longitude: 5.7245},
+	{Name: "Observatory", Latitude: 42.9364, Longitude: 0.1425},
+}
+
+func newTestRecorder(t *testing.T) (*Recorder, *storage.HistoryStore) {
+	t.Helper()
+	history := &storage.HistoryStore{FilePath: filepath.Join(t.TempDir(), "history.json")}
+	return NewRecorderWithHistory(testFavorites, history), history
+}
+
+func testWeather() domain.WeatherData {
+	var weather domain.WeatherData
+	weather.Hourly.Time = []string{"2024-03-10T20:00", "2024-03-10T21:00"}
+	weather.Hourly.CloudCover = []int{10, 20}
+	weather.Hourly.Temperature = []float64{8, 7}
+	return weather
+}
+
+func TestFindFavoriteAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     string
+	}{
+		{name: "exact", lat: 45.1885, lon: 5.7245, want: "Home"},
+		{name: "rounded", lat: 45.19, lon: 5.72, want: ""},
+		{name: "within tolerance", lat: 45.18855, lon: 5.72445, want: "Home"},
+		{name: "float noise", lat: 42.9364 + 1e-12, lon: 0.1425 - 1e-12, want: "Observatory"},
+		{name: "elsewhere", lat: 48.85, lon: 2.35, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			place, ok := findFavoriteAt(testFavorites, tt.lat, tt.lon)
+			if got := place.Name; got != tt.want || ok != (tt.want != "") {
+				t.Errorf("findFavoriteAt(%v, %v) = %q, %v, want %q", tt.lat, tt.lon, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordWeather(t *testing.T) {
+	recorder, history := newTestRecorder(t)
+
+	recorder.RecordWeather(45.1885+1e-9, 5.7245, "best_match", testWeather())
+	recorder.RecordWeather(48.85, 2.35, "best_match", testWeather())
+	recorder.RecordWeather(45.1885, 5.7245, "best_match", domain.WeatherData{})
+
+	if len(history.Records) != 1 {
+		t.Fatalf("len(Records) = %d, want 1 for the favorite alone", len(history.Records))
+	}
+	record := history.Records[0]
+	if record.Place != "Home" || record.Latitude != 45.1885 || record.Model != "best_match" {
+		t.Errorf("record = %s at %v from %s, want Home at 45.1885 from best_match", record.Place, record.Latitude, record.Model)
+	}
+	if len(record.Temperature) != 2 || len(record.CloudCover) != 2 {
+		t.Errorf("record has %d temperatures, %d cloud covers, want 2, 2", len(record.Temperature), len(record.CloudCover))
+	}
+
+	loaded := &storage.HistoryStore{FilePath: history.FilePath}
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Records) != 1 {
+		t.Errorf("saved %d records, want 1", len(loaded.Records))
+	}
+}
+
+func TestRecordModels(t *testing.T) {
+	recorder, history := newTestRecorder(t)
+
+	comparison := domain.ModelComparison{
+		Time: []string{"2024-03-10T20:00"},
+		Models: []domain.ModelSeries{
+			{Model: "ecmwf_ifs025", CloudCover: []int{10}},
+			{Model: "gfs_seamless", CloudCover: []int{-1}},
+		},
+	}
+	recorder.RecordModels(42.9364, 0.1425, comparison)
+
+	if len(history.Records) != 2 {
+		t.Fatalf("len(Records) = %d, want one per model", len(history.Records))
+	}
+	for i, record := range history.Records {
+		if record.Place != "Observatory" || record.Model != comparison.Models[i].Model {
+			t.Errorf("record %d = %s from %s, want Observatory from %s", i, record.Place, record.Model, comparison.Models[i].Model)
+		}
+	}
+}
+
+func TestSetFavorites(t *testing.T) {
+	recorder, history := newTestRecorder(t)
+
+	recorder.SetFavorites(testFavorites[1:])
+	recorder.RecordWeather(45.1885, 5.7245, "best_match", testWeather())
+	if len(history.Records) != 0 {
+		t.Errorf("len(Records) = %d after removing the favorite, want 0", len(history.Records))
+	}
+
+	recorder.SetFavorites(append(testFavorites[1:], domain.Place{Name: "Field", Latitude: 48.85, Longitude: 2.35}))
+	recorder.RecordWeather(48.85, 2.35, "best_match", testWeather())
+	if len(history.Records) != 1 || history.Records[0].Place != "Field" {
+		t.Errorf("Records = %+v, want one of the added favorite", history.Records)
+	}
+}
@@ -0,0 +1,195 @@
+package verification
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+	"driffaud.fr/odin/internal/platform/api/archive"
+	"driffaud.fr/odin/internal/util"
+)
+
+// Stats are the error statistics of a set of verified forecast hours
+type Stats struct {
+	Hours            int     // hours with an observed cloud cover
+	CloudBias        float64 // mean forecast minus observed cloud cover, in percentage points
+	CloudMAE         float64 // mean absolute cloud cover error, in percentage points
+	ClearHitRate     float64 // percent of hours where forecast and observation agree on clear or cloudy
+	TemperatureHours int     // hours with an observed temperature
+	TemperatureBias  float64 // mean forecast minus observed temperature, in °C
+	TemperatureMAE   float64 // mean absolute temperature error, in °C
+}
+
+// Result holds the statistics of one model at one lead time for a place
+type Result struct {
+	Place   string
+	Model   string
+	LeadDay int // 1 for the hours less than 24 hours after the fetch, 2 for the next day...
+	Stats
+}
+
+// resultKey identifies the hours summarized by a result
+type resultKey struct {
+	place, model string
+	leadDay      int
+}
+
+// accumulator sums the errors of the hours of a result
+type accumulator struct {
+	hours, hits, temperatureHours         int
+	cloudError, cloudAbsError             float64
+	temperatureError, temperatureAbsError float64
+}
+
+func (a *accumulator) stats() Stats {
+	var stats Stats
+	if a.hours > 0 {
+		stats.Hours = a.hours
+		stats.CloudBias = a.cloudError / float64(a.hours)
+		stats.CloudMAE = a.cloudAbsError / float64(a.hours)
+		stats.ClearHitRate = 100 * float64(a.hits) / float64(a.hours)
+	}
+	if a.temperatureHours > 0 {
+		stats.TemperatureHours = a.temperatureHours
+		stats.TemperatureBias = a.temperatureError / float64(a.temperatureHours)
+		stats.TemperatureMAE = a.temperatureAbsError / float64(a.temperatureHours)
+	}
+	return stats
+}
+
+// VerifyPlace fetches the observed weather since the oldest record of a place and verifies
+// every record against it. Only the hours up to yesterday are verified. It returns no result
+// when there is nothing to verify yet.
+func VerifyPlace(place domain.Place, records []domain.ForecastRecord, clearThreshold int, now time.Time) ([]Result, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	start := records[0].FetchedAt
+	for _, record := range records {
+		if record.FetchedAt.Before(start) {
+			start = record.FetchedAt
+		}
+	}
+	end := now.AddDate(0, 0, -1)
+	if start.After(end) {
+		return nil, nil
+	}
+
+	observed, err := archive.GetHistoricalWeather(place.Latitude, place.Longitude, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return Verify(records, observed, clearThreshold), nil
+}
+
+// Verify compares forecast records with the observed weather and returns the statistics per
+// place, model and lead day. An hour counts as clear when its cloud cover is at most clearThreshold.
+func Verify(records []domain.ForecastRecord, observed domain.HistoricalWeather, clearThreshold int) []Result {
+	type observation struct {
+		cloudCover, temperature *float64
+	}
+	// Observations are matched by instant, so that the hours of a forecast and of the archive
+	// line up across daylight saving changes
+	observations := make(map[int64]observation, len(observed.Hourly.Time))
+	for i, t := range util.ParseHourlyTimes(observed.Hourly.Time, observed.Location()) {
+		if t.IsZero() {
+			continue
+		}
+		var obs observation
+		if i < len(observed.Hourly.CloudCover) {
+			obs.cloudCover = observed.Hourly.CloudCover[i]
+		}
+		if i < len(observed.Hourly.Temperature) {
+			obs.temperature = observed.Hourly.Temperature[i]
+		}
+		observations[t.Unix()] = obs
+	}
+
+	results := make(map[resultKey]*accumulator)
+	for _, record := range records {
+		for i, t := range util.ParseHourlyTimes(record.Time, record.Location()) {
+			obs, ok := observations[t.Unix()]
+			if !ok || t.IsZero() {
+				continue
+			}
+			lead := t.Sub(record.FetchedAt)
+			if lead < 0 {
+				continue
+			}
+
+			key := resultKey{place: record.Place, model: record.Model, leadDay: int(lead/(24*time.Hour)) + 1}
+			acc, ok := results[key]
+			if !ok {
+				acc = &accumulator{}
+				results[key] = acc
+			}
+
+			if obs.cloudCover != nil && i < len(record.CloudCover) && record.CloudCover[i] >= 0 {
+				forecast, actual := float64(record.CloudCover[i]), *obs.cloudCover
+				acc.hours++
+				acc.cloudError += forecast - actual
+				acc.cloudAbsError += math.Abs(forecast - actual)
+				if (forecast <= float64(clearThreshold)) == (actual <= float64(clearThreshold)) {
+					acc.hits++
+				}
+			}
+			if obs.temperature != nil && i < len(record.Temperature) {
+				err := record.Temperature[i] - *obs.temperature
+				acc.temperatureHours++
+				acc.temperatureError += err
+				acc.temperatureAbsError += math.Abs(err)
+			}
+		}
+	}
+
+	var verified []Result
+	for key, acc := range results {
+		if acc.hours == 0 && acc.temperatureHours == 0 {
+			continue
+		}
+		verified = append(verified, Result{
+			Place:   key.place,
+			Model:   key.model,
+			LeadDay: key.leadDay,
+			Stats:   acc.stats(),
+		})
+	}
+	sortResults(verified)
+	return verified
+}
+
+// Summarize merges the results of a model over every lead day
+func Summarize(results []Result, model string) Stats {
+	var acc accumulator
+	for _, result := range results {
+		if result.Model != model {
+			continue
+		}
+		hours, temperatureHours := float64(result.Hours), float64(result.TemperatureHours)
+		acc.hours += result.Hours
+		acc.hits += int(math.Round(result.ClearHitRate * hours / 100))
+		acc.cloudError += result.CloudBias * hours
+		acc.cloudAbsError += result.CloudMAE * hours
+		acc.temperatureHours += result.TemperatureHours
+		acc.temperatureError += result.TemperatureBias * temperatureHours
+		acc.temperatureAbsError += result.TemperatureMAE * temperatureHours
+	}
+	return acc.stats()
+}
+
+// sortResults orders results by place, model and lead day
+func sortResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Place != b.Place {
+			return a.Place < b.Place
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.LeadDay < b.LeadDay
+	})
+}
@@ -0,0 +1,42 @@
+package verification
+
+import (
+	"testing"
+	"time"
+
+	"driffaud.fr/odin/internal/domain"
+)
+
+func TestVerifyAcrossDaylightSavingChange(t *testing.T) {
+	// Fetched on the eve of the spring change in Paris, while the offset was still +1
+	record := domain.ForecastRecord{
+		Place:      "Home",
+		Model:      "best_match",
+		FetchedAt:  time.Date(2024, time.March, 30, 9, 30, 0, 0, time.UTC),
+		Timezone:   "Europe/Paris",
+		UTCOffset:  3600,
+		Time:       []string{"2024-03-31T01:00", "2024-03-31T03:00", "2024-03-31T11:00"},
+		CloudCover: []int{10, 20, 30},
+	}
+
+	var observed domain.HistoricalWeather
+	observed.Timezone = "Europe/Paris"
+	observed.UTCOffset = 7200
+	observed.Hourly.Time = []string{"2024-03-31T01:00", "2024-03-31T03:00", "2024-03-31T11:00"}
+	clear, cloudy, overcast := 0.0, 40.0, 100.0
+	observed.Hourly.CloudCover = []*float64{&clear, &cloudy, &overcast}
+
+	// 11:00 in summer time is 23.5 hours after the fetch: still the first lead day
+	results := Verify([]domain.ForecastRecord{record}, observed, 30)
+	if len(results) != 1 {
+		t.Fatalf("Verify returned %d results, want 1: %+v", len(results), results)
+	}
+	got := results[0]
+	if got.LeadDay != 1 || got.Hours != 3 {
+		t.Errorf("Verify = lead day %d over %d hours, want lead day 1 over 3 hours", got.LeadDay, got.Hours)
+	}
+	// Forecast minus observed: 10, -20 and -70
+	if want := -80.0 / 3; got.CloudBias != want {
+		t.Errorf("cloud bias = %.2f, want %.2f", got.CloudBias, want)
+	}
+}